@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/profx5/jordi/internal/app"
 	"github.com/profx5/jordi/internal/config"
@@ -18,15 +20,73 @@ var (
 	flags        = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	help         = flags.Bool("help", false, "Print usage instructions and exit.")
 	printVersion = flags.Bool("version", false, "Print version and exit.")
-	insecure     = flags.Bool("insecure", false, `Skip TLS certificate verification. (NOT SECURE!)`)
+	plaintext    = flags.Bool("plaintext", false, `Use plain-text HTTP/2 when connecting to the server (no TLS).`)
+	insecure     = flags.Bool("insecure", false, `Deprecated alias of -plaintext.`)
+	skipVerify   = flags.Bool("insecure-skip-verify", false, `Use TLS but skip the server certificate verification. (NOT SECURE!)`)
+	cacert       = flags.String("cacert", "", `File containing trusted root certificates for verifying the server.`)
+	cert         = flags.String("cert", "", `File containing the client certificate (public key), to present
+to the server. Must also provide -key option.`)
+	key = flags.String("key", "", `File containing the client private key, to present to the server.
+Must also provide -cert option.`)
+	serverName = flags.String("servername", "", `Override the server name used to verify the server certificate.`)
+	useReflect = flags.Bool("use-reflection", false, `Use the server reflection together with -proto or -protoset files.
+Symbols missing on the server are resolved from the local files.
+The reflection is always used when no local files are given.`)
+	maxTime = flags.Float64("max-time", 0, `The maximum time, in seconds, a call can take. Fractional values
+are allowed. The deadline of a single request can be changed in
+the request editor. Zero means no deadline.`)
+	connectTimeout = flags.Float64("connect-timeout", 0, `The maximum time, in seconds, to wait for the connection to be
+established. Defaults to 10 seconds.`)
+	keepaliveTime = flags.Float64("keepalive-time", 0, `If present, the maximum idle time in seconds, after which a keepalive
+probe is sent. If the connection remains idle and no keepalive response
+is received for this same period then the connection is closed.`)
+	maxMsgSz = flags.Int("max-msg-sz", 0, `The maximum encoded size of a message, in bytes, that jordi can send
+or receive. Defaults to 4,194,304 (4 megabytes).`)
+	collections = flags.String("collections", "", `The directory of the saved request collections, one JSON file per
+collection. Defaults to $XDG_CONFIG_HOME/jordi/collections.`)
+	environment = flags.String("env", "", `The name of the environment of the config file whose variables
+replace the {{name}} placeholders of requests, metadata and the
+address. Can be switched in the UI.`)
+	headers     multiString
+	protoFiles  multiString
+	importPaths multiString
+	protosets   multiString
 )
 
+func init() {
+	flags.Var(&headers, "H", `Additional header in 'name: value' format, may be specified
+more than once. Values of '-bin' headers are base64-encoded.
+Pre-populates the request metadata editor.`)
+	flags.Var(&protoFiles, "proto", `The name of a proto source file, may be specified more than once.
+Source files are used to describe services instead of the server
+reflection. Imports are resolved against the -import-path entries.`)
+	flags.Var(&importPaths, "import-path", `The path to a directory from which proto sources can be imported,
+for use with -proto flags. May be specified more than once.
+Defaults to the current working directory.`)
+	flags.Var(&protosets, "protoset", `The name of a file containing an encoded FileDescriptorSet, may be
+specified more than once. Protosets are used to describe services
+instead of the server reflection.`)
+}
+
+type multiString []string
+
+func (s *multiString) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *multiString) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func usage() {
 	fmt.Fprintf(os.Stderr, `Usage:
 %s [flags] [address] [method]
 
 The 'address' will typically be in the form "host:port" where host can be an IP
 address or a hostname and port is a numeric port or service name.
+Unix domain sockets are addressed as "unix:///path/to.sock" (or "unix:path")
+and abstract sockets as "unix-abstract:name".
 
 The optional 'method' is the fully qualified name of the method to invoke, in the form
 "package.Service/Method".
@@ -37,6 +97,10 @@ Available flags:
 	flags.PrintDefaults()
 }
 
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
+
 func fail(err error, msg string, args ...interface{}) {
 	if err != nil {
 		msg += ": %v"
@@ -84,7 +148,33 @@ func main() {
 		fail(nil, "Too many arguments.")
 	}
 
-	config := config.New(target, method, *insecure)
+	file, err := config.LoadDefaultFile()
+	if err != nil {
+		fail(err, "Failed to load config")
+	}
+	config := config.New(target, method)
+	config.Plaintext = *plaintext || *insecure
+	config.CACert = *cacert
+	config.Cert = *cert
+	config.Key = *key
+	config.ServerName = *serverName
+	config.InsecureSkipVerify = *skipVerify
+	config.Headers = headers
+	config.ProtoFiles = protoFiles
+	config.ImportPaths = importPaths
+	config.Protosets = protosets
+	config.UseReflection = *useReflect
+	config.MaxTime = seconds(*maxTime)
+	config.ConnectTimeout = seconds(*connectTimeout)
+	config.KeepaliveTime = seconds(*keepaliveTime)
+	config.MaxMsgSize = *maxMsgSz
+	config.Targets = file.Targets
+	config.CollectionsDir = *collections
+	config.Environment = *environment
+	config.Environments = file.Environments
+	if err := config.Validate(); err != nil {
+		fail(nil, "%v", err)
+	}
 	app := app.New(config)
 	if err := app.Run(context.Background()); err != nil {
 		fail(err, "Failed")
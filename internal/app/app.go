@@ -20,16 +20,36 @@ func New(config config.Config) *App {
 
 func (a *App) Run(ctx context.Context) error {
 	opts := grpc.DefaultOpts()
-	opts.Insecure = a.config.Insecure
-	grpcWrapper, err := grpc.New(ctx, a.config.Target, opts)
-	if err != nil {
-		return err
+	opts.Network = a.config.Network
+	opts.Plaintext = a.config.Plaintext
+	opts.CACert = a.config.CACert
+	opts.Cert = a.config.Cert
+	opts.Key = a.config.Key
+	opts.ServerName = a.config.ServerName
+	opts.InsecureSkipVerify = a.config.InsecureSkipVerify
+	opts.ProtoFiles = a.config.ProtoFiles
+	opts.ImportPaths = a.config.ImportPaths
+	opts.Protosets = a.config.Protosets
+	opts.UseReflection = a.config.UseReflection
+	if a.config.ConnectTimeout > 0 {
+		opts.ConnectTimeout = a.config.ConnectTimeout
+	}
+	opts.KeepaliveTime = a.config.KeepaliveTime
+	opts.MaxMsgSize = a.config.MaxMsgSize
+	// the connection is made by the TUI, so it starts even when the target is down
+	dial := func(ctx context.Context, target config.Target) (*grpc.Wrapper, error) {
+		targetOpts := opts
+		network, address := config.ParseTarget(target.Address)
+		targetOpts.Network = network
+		targetOpts.Plaintext = target.Plaintext
+		return grpc.New(ctx, address, targetOpts)
 	}
-	defer grpcWrapper.Close()
-	store := store.New(grpcWrapper.Target)
-	defer store.Flush()
+	global := store.NewGlobal()
+	store := store.New(a.config.Address)
 
-	root := tui.NewRoot(a.config, grpcWrapper, store)
+	// closing the root flushes the stores
+	root := tui.NewRoot(a.config, dial, store, global)
+	defer root.Close()
 
 	p := tea.NewProgram(root, tea.WithAltScreen(), tea.WithContext(ctx))
 	if _, err := p.Run(); err != nil {
@@ -0,0 +1,212 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDirName = "jordi/collections"
+	fileExt        = ".json"
+	// DefaultName is the collection of requests saved without one.
+	DefaultName = "default"
+)
+
+type (
+	// Dir keeps collections as JSON files, one file per collection.
+	Dir struct {
+		path string
+	}
+	// Collection is a named group of saved requests, not tied to a target.
+	Collection struct {
+		Name     string    `json:"-"`
+		Requests []Request `json:"requests"`
+	}
+	// Request is a named request of a collection.
+	Request struct {
+		Name     string   `json:"name"`
+		Method   string   `json:"method"`
+		Headers  []string `json:"headers,omitempty"`
+		Deadline string   `json:"deadline,omitempty"`
+		Payload  Payload  `json:"payload"`
+	}
+	// Payload is written to the file as nested JSON, text that is not JSON is kept as a string.
+	Payload string
+)
+
+// NewDir opens the collections directory, empty path is the XDG config directory.
+func NewDir(path string) *Dir {
+	if path == "" {
+		path = filepath.Join(xdg.ConfigHome, defaultDirName)
+	}
+	return &Dir{path: path}
+}
+
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// List loads all collections sorted by name.
+func (d *Dir) List() ([]Collection, error) {
+	entries, err := os.ReadDir(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collections directory '%s'", d.path)
+	}
+	collections := []Collection{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		collection, err := d.Load(strings.TrimSuffix(entry.Name(), fileExt))
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	return collections, nil
+}
+
+// Load reads the collection, a missing one is empty.
+func (d *Dir) Load(name string) (Collection, error) {
+	path, err := d.file(name)
+	if err != nil {
+		return Collection{}, err
+	}
+	collection := Collection{Name: name}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return collection, nil
+	}
+	if err != nil {
+		return Collection{}, errors.Wrapf(err, "failed to read collection '%s'", path)
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return Collection{}, errors.Wrapf(err, "failed to decode collection '%s'", path)
+	}
+	return collection, nil
+}
+
+// Save adds the request to the collection, a request with the same name is replaced.
+func (d *Dir) Save(name string, request Request) error {
+	if strings.TrimSpace(request.Name) == "" {
+		return errors.New("request name is empty")
+	}
+	collection, err := d.Load(name)
+	if err != nil {
+		return err
+	}
+	if i := collection.find(request.Name); i >= 0 {
+		collection.Requests[i] = request
+	} else {
+		collection.Requests = append(collection.Requests, request)
+	}
+	return d.write(collection)
+}
+
+// Rename renames the request of the collection, the new name must not be taken.
+func (d *Dir) Rename(name, oldName, newName string) error {
+	if strings.TrimSpace(newName) == "" {
+		return errors.New("request name is empty")
+	}
+	collection, err := d.Load(name)
+	if err != nil {
+		return err
+	}
+	i := collection.find(oldName)
+	if i < 0 {
+		return errors.Errorf("request '%s' not found in collection '%s'", oldName, name)
+	}
+	if newName != oldName && collection.find(newName) >= 0 {
+		return errors.Errorf("request '%s' already exists in collection '%s'", newName, name)
+	}
+	collection.Requests[i].Name = newName
+	return d.write(collection)
+}
+
+// Delete removes the request from the collection.
+func (d *Dir) Delete(name, requestName string) error {
+	collection, err := d.Load(name)
+	if err != nil {
+		return err
+	}
+	i := collection.find(requestName)
+	if i < 0 {
+		return errors.Errorf("request '%s' not found in collection '%s'", requestName, name)
+	}
+	collection.Requests = append(collection.Requests[:i], collection.Requests[i+1:]...)
+	return d.write(collection)
+}
+
+func (c Collection) find(name string) int {
+	for i, request := range c.Requests {
+		if request.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Dir) file(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.Errorf("invalid collection name '%s'", name)
+	}
+	return filepath.Join(d.path, name+fileExt), nil
+}
+
+// write replaces the collection file, the new content is renamed over the old one.
+func (d *Dir) write(collection Collection) error {
+	path, err := d.file(collection.Name)
+	if err != nil {
+		return err
+	}
+	if collection.Requests == nil {
+		collection.Requests = []Request{}
+	}
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode collection '%s'", collection.Name)
+	}
+	if err := os.MkdirAll(d.path, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create collections directory '%s'", d.path)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write collection '%s'", path)
+	}
+	return errors.Wrapf(os.Rename(tmp, path), "failed to write collection '%s'", path)
+}
+
+func (p Payload) MarshalJSON() ([]byte, error) {
+	if json.Valid([]byte(p)) {
+		return []byte(p), nil
+	}
+	return json.Marshal(string(p))
+}
+
+func (p *Payload) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*p = Payload(s)
+		return nil
+	}
+	buf := bytes.Buffer{}
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	*p = Payload(buf.String())
+	return nil
+}
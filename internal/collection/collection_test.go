@@ -0,0 +1,76 @@
+package collection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndList(t *testing.T) {
+	dir := NewDir(filepath.Join(t.TempDir(), "collections"))
+
+	collections, err := dir.List()
+	require.NoError(t, err)
+	assert.Empty(t, collections)
+
+	happy := Request{
+		Name:     "create user – happy path",
+		Method:   "users.v1.Users.Create",
+		Headers:  []string{"x-tenant: acme"},
+		Deadline: "5s",
+		Payload:  `{"email": "a@example.com"}`,
+	}
+	require.NoError(t, dir.Save("users", happy))
+	require.NoError(t, dir.Save("users", Request{Name: "create user – missing email", Method: "users.v1.Users.Create", Payload: "{}"}))
+	require.NoError(t, dir.Save("accounts", Request{Name: "get", Method: "accounts.v1.Accounts.Get", Payload: `{"id": {{id}}}`}))
+
+	// saving under the same name replaces the request
+	happy.Payload = `{"email": "b@example.com"}`
+	require.NoError(t, dir.Save("users", happy))
+
+	collections, err = dir.List()
+	require.NoError(t, err)
+	require.Len(t, collections, 2)
+	assert.Equal(t, "accounts", collections[0].Name)
+	assert.Equal(t, Payload(`{"id": {{id}}}`), collections[0].Requests[0].Payload)
+	assert.Equal(t, "users", collections[1].Name)
+	require.Len(t, collections[1].Requests, 2)
+	assert.Equal(t, "create user – happy path", collections[1].Requests[0].Name)
+	assert.Equal(t, Payload("{\n  \"email\": \"b@example.com\"\n}"), collections[1].Requests[0].Payload)
+	assert.Equal(t, []string{"x-tenant: acme"}, collections[1].Requests[0].Headers)
+	assert.Equal(t, "5s", collections[1].Requests[0].Deadline)
+
+	// the payload is nested JSON in the file
+	data, err := os.ReadFile(filepath.Join(dir.Path(), "users.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"payload": {
+        "email": "b@example.com"
+      }`)
+}
+
+func TestRenameAndDelete(t *testing.T) {
+	dir := NewDir(t.TempDir())
+	require.NoError(t, dir.Save("users", Request{Name: "a", Method: "svc.A", Payload: "{}"}))
+	require.NoError(t, dir.Save("users", Request{Name: "b", Method: "svc.B", Payload: "{}"}))
+
+	assert.EqualError(t, dir.Rename("users", "a", "b"), "request 'b' already exists in collection 'users'")
+	assert.EqualError(t, dir.Rename("users", "missing", "c"), "request 'missing' not found in collection 'users'")
+	require.NoError(t, dir.Rename("users", "a", "c"))
+
+	require.NoError(t, dir.Delete("users", "b"))
+	assert.EqualError(t, dir.Delete("users", "b"), "request 'b' not found in collection 'users'")
+
+	collection, err := dir.Load("users")
+	require.NoError(t, err)
+	assert.Equal(t, []Request{{Name: "c", Method: "svc.A", Payload: "{}"}}, collection.Requests)
+}
+
+func TestInvalidNames(t *testing.T) {
+	dir := NewDir(t.TempDir())
+	assert.EqualError(t, dir.Save("../users", Request{Name: "a"}), "invalid collection name '../users'")
+	assert.EqualError(t, dir.Save("", Request{Name: "a"}), "invalid collection name ''")
+	assert.EqualError(t, dir.Save("users", Request{Name: " "}), "request name is empty")
+}
@@ -1,15 +1,98 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	unixScheme         = "unix:"
+	unixAbstractScheme = "unix-abstract:"
+)
+
 type Config struct {
-	Target   string
-	Method   string
-	Insecure bool
+	Target string
+	// Network and Address are parsed from the Target to dial the server
+	Network            string
+	Address            string
+	Method             string
+	Plaintext          bool
+	CACert             string
+	Cert               string
+	Key                string
+	ServerName         string
+	InsecureSkipVerify bool
+	Headers            []string
+	ProtoFiles         []string
+	ImportPaths        []string
+	Protosets          []string
+	UseReflection      bool
+	// MaxTime is the default deadline of calls, zero means no deadline
+	MaxTime time.Duration
+	// zero connection options keep the defaults of grpc.DefaultOpts
+	ConnectTimeout time.Duration
+	KeepaliveTime  time.Duration
+	MaxMsgSize     int
+	// Targets are the servers of the config file to switch between
+	Targets []Target
+	// CollectionsDir keeps the saved requests, empty means the XDG config directory
+	CollectionsDir string
+	// Environment is the name of the selected one of the Environments
+	Environment  string
+	Environments map[string]Environment
+}
+
+func New(target, method string) Config {
+	network, address := ParseTarget(target)
+	return Config{Target: target, Network: network, Address: address, Method: method}
 }
 
-func New(target, method string, insecure bool) Config {
-	return Config{Target: target, Method: method, Insecure: insecure}
+// ParseTarget splits the target into the network and the address to dial.
+// Besides "host:port" it accepts "unix:path", "unix:///absolute/path" and
+// "unix-abstract:name" targets.
+func ParseTarget(target string) (string, string) {
+	switch {
+	case strings.HasPrefix(target, unixAbstractScheme):
+		return "unix", "@" + strings.TrimPrefix(target, unixAbstractScheme)
+	case strings.HasPrefix(target, unixScheme+"//"):
+		return "unix", strings.TrimPrefix(target, unixScheme+"//")
+	case strings.HasPrefix(target, unixScheme):
+		return "unix", strings.TrimPrefix(target, unixScheme)
+	}
+	return "tcp", target
 }
 
 func (c Config) Validate() error {
+	if c.Address == "" || c.Address == "@" {
+		return fmt.Errorf("empty address of the target '%s'", c.Target)
+	}
+	if c.Plaintext && (c.CACert != "" || c.Cert != "" || c.Key != "" || c.ServerName != "" || c.InsecureSkipVerify) {
+		return fmt.Errorf("TLS flags are not used with -plaintext connections")
+	}
+	if (c.Cert == "") != (c.Key == "") {
+		return fmt.Errorf("the -cert and -key arguments must be used together")
+	}
+	if len(c.ProtoFiles) > 0 && len(c.Protosets) > 0 {
+		return fmt.Errorf("use either -protoset files or -proto files, but not both")
+	}
+	if c.MaxTime < 0 {
+		return fmt.Errorf("the -max-time argument must not be negative")
+	}
+	if c.ConnectTimeout < 0 {
+		return fmt.Errorf("the -connect-timeout argument must not be negative")
+	}
+	if c.KeepaliveTime < 0 {
+		return fmt.Errorf("the -keepalive-time argument must not be negative")
+	}
+	if c.MaxMsgSize < 0 {
+		return fmt.Errorf("the -max-msg-sz argument must not be negative")
+	}
+	if _, ok := c.Environments[c.Environment]; c.Environment != "" && !ok {
+		return fmt.Errorf("unknown environment '%s'", c.Environment)
+	}
+	if len(c.ImportPaths) > 0 && len(c.ProtoFiles) == 0 {
+		return fmt.Errorf("the -import-path argument is only used with -proto files")
+	}
 	return nil
 }
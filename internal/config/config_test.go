@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		network string
+		address string
+	}{
+		{"localhost:9000", "tcp", "localhost:9000"},
+		{"unix:///var/run/app.sock", "unix", "/var/run/app.sock"},
+		{"unix:relative.sock", "unix", "relative.sock"},
+		{"unix:/var/run/app.sock", "unix", "/var/run/app.sock"},
+		{"unix-abstract:app", "unix", "@app"},
+	}
+	for _, test := range tests {
+		network, address := ParseTarget(test.target)
+		assert.Equal(t, test.network, network, test.target)
+		assert.Equal(t, test.address, address, test.target)
+	}
+}
+
+func TestValidateEmptyAddress(t *testing.T) {
+	assert.Error(t, New("unix://", "").Validate())
+	assert.Error(t, New("unix-abstract:", "").Validate())
+	assert.NoError(t, New("unix:///tmp/app.sock", "").Validate())
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	config := New("localhost:9000", "")
+	config.Environments = map[string]Environment{"local": {"tenant": "acme"}}
+
+	config.Environment = "local"
+	assert.NoError(t, config.Validate())
+
+	config.Environment = "prod"
+	assert.EqualError(t, config.Validate(), "unknown environment 'prod'")
+}
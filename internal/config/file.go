@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/adrg/xdg"
+	"github.com/pkg/errors"
+	"github.com/profx5/jordi/internal/vars"
+)
+
+const configFileName = "jordi/config.json"
+
+type (
+	// File is the user configuration file, optional.
+	File struct {
+		Targets      []Target               `json:"targets"`
+		Environments map[string]Environment `json:"environments"`
+	}
+	// Target is a server to connect to.
+	Target struct {
+		Name      string `json:"name,omitempty"`
+		Address   string `json:"address"`
+		Plaintext bool   `json:"plaintext,omitempty"`
+	}
+	// Environment is a named set of variables substituted into requests.
+	Environment map[string]string
+)
+
+// Title is the name of the target, or its address when it has no name.
+func (t Target) Title() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Address
+}
+
+// Same tells whether both targets connect to the same server the same way, names aside.
+func (t Target) Same(other Target) bool {
+	return t.Address == other.Address && t.Plaintext == other.Plaintext
+}
+
+// LoadDefaultFile loads the configuration file from the XDG config directory,
+// a missing file is an empty configuration.
+func LoadDefaultFile() (File, error) {
+	path, err := xdg.SearchConfigFile(configFileName)
+	if err != nil {
+		return File{}, nil
+	}
+	return LoadFile(path)
+}
+
+func LoadFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, errors.Wrapf(err, "failed to read config file '%s'", path)
+	}
+	file := File{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return File{}, errors.Wrapf(err, "failed to decode config file '%s'", path)
+	}
+	for i, target := range file.Targets {
+		if target.Address == "" {
+			return File{}, errors.Errorf("config file '%s': target #%d has no address", path, i+1)
+		}
+	}
+	for name, environment := range file.Environments {
+		for variable := range environment {
+			if !vars.IsValidName(variable) {
+				return File{}, errors.Errorf("config file '%s': invalid name of variable '%s' in environment '%s'", path, variable, name)
+			}
+		}
+	}
+	return file, nil
+}
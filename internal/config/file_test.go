@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeFile(t, `{"targets": [
+		{"name": "local", "address": "localhost:9000", "plaintext": true},
+		{"address": "staging.example.com:443"}
+	]}`)
+
+	file, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Target{
+		{Name: "local", Address: "localhost:9000", Plaintext: true},
+		{Address: "staging.example.com:443"},
+	}, file.Targets)
+	assert.Equal(t, "local", file.Targets[0].Title())
+	assert.Equal(t, "staging.example.com:443", file.Targets[1].Title())
+}
+
+func TestLoadFileEnvironments(t *testing.T) {
+	path := writeFile(t, `{"environments": {
+		"local": {"tenant": "acme", "user_id": "42"},
+		"staging": {"tenant": "acme-staging"}
+	}}`)
+
+	file, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Environment{
+		"local":   {"tenant": "acme", "user_id": "42"},
+		"staging": {"tenant": "acme-staging"},
+	}, file.Environments)
+
+	_, err = LoadFile(writeFile(t, `{"environments": {"local": {"user-id": "42"}}}`))
+	assert.Error(t, err)
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	_, err := LoadFile(writeFile(t, `{"targets": [{"name": "local"}]}`))
+	assert.Error(t, err)
+
+	_, err = LoadFile(writeFile(t, `{"targets": `))
+	assert.Error(t, err)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoprint"
+)
+
+// descriptorPrinter keeps the leading and trailing comments of elements,
+// unlike the printer of grpcurl.GetDescriptorText which drops trailing ones.
+var descriptorPrinter = &protoprint.Printer{
+	Compact:                  true,
+	OmitComments:             protoprint.CommentsDetached,
+	SortElements:             true,
+	ForceFullyQualifiedNames: true,
+}
+
+// describe renders the descriptor as proto source.
+func describe(dsc desc.Descriptor) (string, error) {
+	text, err := descriptorPrinter.PrintProtoToString(dsc)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(text, "\n"), nil
+}
+
+// comment returns the documentation of the element, it is empty when the
+// descriptor source has no source info, e.g. the server reflection usually.
+func comment(dsc desc.Descriptor) string {
+	info := dsc.GetSourceInfo()
+	if info == nil {
+		return ""
+	}
+	comments := []string{}
+	for _, text := range []string{info.GetLeadingComments(), info.GetTrailingComments()} {
+		if text = strings.TrimSpace(text); text != "" {
+			comments = append(comments, text)
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+// commentLines formats the comment as proto line comments.
+func commentLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeSymbol renders the symbol and lists the types it refers to.
+func describeSymbol(source grpcurl.DescriptorSource, name string) (SymbolInfo, error) {
+	dsc, err := source.FindSymbol(name)
+	if err != nil {
+		return SymbolInfo{}, err
+	}
+	text, err := describe(dsc)
+	if err != nil {
+		return SymbolInfo{}, err
+	}
+	info := SymbolInfo{Name: dsc.GetFullyQualifiedName(), Text: text}
+	switch dsc := dsc.(type) {
+	case *desc.MethodDescriptor:
+		info.Kind = "method"
+		info.References = []TypeRef{
+			{Field: "request", Type: dsc.GetInputType().GetFullyQualifiedName()},
+			{Field: "response", Type: dsc.GetOutputType().GetFullyQualifiedName()},
+		}
+	case *desc.MessageDescriptor:
+		info.Kind = "message"
+		info.References = messageReferences(dsc, "")
+	case *desc.EnumDescriptor:
+		info.Kind = "enum"
+	case *desc.ServiceDescriptor:
+		info.Kind = "service"
+	default:
+		return SymbolInfo{}, fmt.Errorf("%s is not a type", name)
+	}
+	return info, nil
+}
+
+// messageReferences lists message and enum types of the fields, fields of
+// nested messages are prefixed with the nested message name.
+func messageReferences(msg *desc.MessageDescriptor, prefix string) []TypeRef {
+	refs := []TypeRef{}
+	for _, field := range msg.GetFields() {
+		typeField := field
+		// map entries are synthetic messages, the value type is the interesting one
+		if field.IsMap() {
+			typeField = field.GetMapValueType()
+		}
+		if typeName := fieldTypeName(typeField); typeName != "" {
+			refs = append(refs, TypeRef{Field: prefix + field.GetName(), Type: typeName})
+		}
+	}
+	for _, nested := range msg.GetNestedMessageTypes() {
+		if !nested.IsMapEntry() {
+			refs = append(refs, messageReferences(nested, prefix+nested.GetName()+".")...)
+		}
+	}
+	return refs
+}
+
+func fieldTypeName(field *desc.FieldDescriptor) string {
+	if msgType := field.GetMessageType(); msgType != nil {
+		return msgType.GetFullyQualifiedName()
+	}
+	if enumType := field.GetEnumType(); enumType != nil {
+		return enumType.GetFullyQualifiedName()
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const probeTimeout = 3 * time.Second
+
+// diagnoseDial probes the address to explain a failed connection, e.g. a TLS
+// client talking to a plain-text server or the other way around.
+// The dial error is returned as is when there is nothing to suggest.
+func diagnoseDial(ctx context.Context, network, address string, tlsConfig *tls.Config, dialErr error) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		if strings.Contains(dialErr.Error(), err.Error()) {
+			return dialErr
+		}
+		return fmt.Errorf("%v: %v", dialErr, err)
+	}
+	defer conn.Close()
+
+	if tlsConfig == nil {
+		// probe with verification skipped, only the protocol matters here
+		tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+		if tlsConn.HandshakeContext(ctx) == nil {
+			return fmt.Errorf("%v: server at %s expects TLS, drop the -plaintext flag", dialErr, address)
+		}
+		return dialErr
+	}
+
+	probeConfig := tlsConfig.Clone()
+	// ALPN does not matter for the probe and may fail the handshake
+	probeConfig.NextProtos = nil
+	if probeConfig.ServerName == "" {
+		probeConfig.ServerName = hostname(address)
+	}
+	err = tls.Client(conn, probeConfig).HandshakeContext(ctx)
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &recordErr):
+		return fmt.Errorf("%v: server at %s does not speak TLS, use the -plaintext flag", dialErr, address)
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return fmt.Errorf("%v: server certificate is not trusted (%v), use -cacert, -servername or -insecure-skip-verify", dialErr, err)
+	}
+	return dialErr
+}
+
+func hostname(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
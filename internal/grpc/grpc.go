@@ -5,6 +5,10 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/fullstorydev/grpcurl"
@@ -12,23 +16,40 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/pkg/errors"
 	"github.com/profx5/jordi/internal/version"
+	// registers google.rpc error details to decode status details not known by the server
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
-	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type (
 	Opts struct {
+		// Network to dial the target with, "tcp" by default
+		Network        string
 		ConnectTimeout time.Duration
 		KeepaliveTime  time.Duration
 		MaxMsgSize     int
-		Insecure       bool
+		Plaintext      bool
+		// TLS settings, ignored for plain-text connections
+		CACert             string
+		Cert               string
+		Key                string
+		ServerName         string
+		InsecureSkipVerify bool
+		// ProtoFiles or Protosets are used as the descriptor source instead of
+		// the server reflection, unless UseReflection is set
+		ProtoFiles    []string
+		ImportPaths   []string
+		Protosets     []string
+		UseReflection bool
 	}
 	Wrapper struct {
 		cc         *grpc.ClientConn
@@ -36,24 +57,71 @@ type (
 		descSource grpcurl.DescriptorSource
 		reqCancel  func()
 		Target     string
+		// Reflection is the detected reflection service, empty if it is not used
+		Reflection string
+		// localFiles tells whether -proto or -protoset files describe services
+		localFiles bool
 	}
 	TypeAndError[T any] struct {
 		Result T
 		Err    error
 	}
+	ServiceInfo struct {
+		Name    string
+		Comment string
+	}
+	// MethodInfo is the signature of a method.
+	MethodInfo struct {
+		Name            string
+		Comment         string
+		InputType       string
+		OutputType      string
+		ClientStreaming bool
+		ServerStreaming bool
+	}
+	// SymbolInfo is the proto source of a symbol and the types it refers to.
+	SymbolInfo struct {
+		Name       string
+		Kind       string
+		Text       string
+		References []TypeRef
+	}
+	// TypeRef is a message or enum type used by a field.
+	TypeRef struct {
+		Field string
+		Type  string
+	}
 	InDesc struct {
-		Desc    string
-		Example string
-		Err     error
+		Desc            string
+		Example         string
+		ClientStreaming bool
+		Err             error
+	}
+	// Stream feeds request messages of a client-streaming or bidi call.
+	Stream struct {
+		lock     sync.Mutex
+		requests chan string
+		done     <-chan struct{}
+		closed   bool
 	}
 	gRPCEventType int
 	Event         struct {
 		Type    gRPCEventType
 		Payload interface{}
 		Err     error
+		Time    time.Time
+	}
+	// CallStatus is the payload of ReceivedTrailers events.
+	CallStatus struct {
+		Code    string
+		Message string
+		// Details are google.rpc.Status details rendered to JSON
+		Details  []string
+		Trailers metadata.MD
 	}
 	gRPCEventHandler struct {
-		c chan<- Event
+		c         chan<- Event
+		marshaler *jsonpb.Marshaler
 	}
 )
 
@@ -76,11 +144,17 @@ var (
 
 func DefaultOpts() Opts {
 	return Opts{
-		ConnectTimeout: 10 * time.Second, // TODO: make this configurable
+		Network:        "tcp",
+		ConnectTimeout: 10 * time.Second,
 	}
 }
 
 func New(ctx context.Context, target string, connOpts Opts) (*Wrapper, error) {
+	fileSource, err := newFileSource(connOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	var opts []grpc.DialOption
 	if connOpts.KeepaliveTime > 0 {
 		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
@@ -89,76 +163,190 @@ func New(ctx context.Context, target string, connOpts Opts) (*Wrapper, error) {
 		}))
 	}
 	if connOpts.MaxMsgSize > 0 {
-		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(connOpts.MaxMsgSize)))
+		opts = append(opts, grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(connOpts.MaxMsgSize),
+			grpc.MaxCallSendMsgSize(connOpts.MaxMsgSize),
+		))
 	}
 	var creds credentials.TransportCredentials
-	if connOpts.Insecure {
+	var tlsConfig *tls.Config
+	if connOpts.Plaintext {
 		creds = insecure.NewCredentials()
 	} else {
-		creds = credentials.NewTLS(&tls.Config{})
+		tlsConfig, err = newTLSConfig(connOpts)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
 	}
 
 	userAgent := "jordi/" + version.Version
 	opts = append(opts, grpc.WithUserAgent(userAgent))
 
-	network := "tcp"
+	network := connOpts.Network
 
-	type res struct {
-		clientConn *grpc.ClientConn
-		err        error
-	}
-	resultChan := make(chan res)
-	go func() {
-		cc, err := grpcurl.BlockingDial(ctx, network, target, creds, opts...)
-		if err != nil {
-			resultChan <- res{err: err}
-			return
-		}
-		resultChan <- res{clientConn: cc}
-	}()
-	var clientConn *grpc.ClientConn
+	// the dial gives up once timed out, a server coming up later is not connected to
 	timeoutCtx, cancel := context.WithTimeout(ctx, connOpts.ConnectTimeout)
 	defer cancel()
-	select {
-	case <-timeoutCtx.Done():
-		return nil, fmt.Errorf("connection timed out")
-	case r := <-resultChan:
-		if r.err != nil {
-			return nil, r.err
+	clientConn, err := grpcurl.BlockingDial(timeoutCtx, network, target, creds, opts...)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, diagnoseDial(ctx, network, target, tlsConfig, fmt.Errorf("connection timed out"))
+	} else if err != nil {
+		return nil, diagnoseDial(ctx, network, target, tlsConfig, err)
+	}
+	var refClient *grpcreflect.Client
+	var reflection string
+	descSource := fileSource
+	if fileSource == nil || connOpts.UseReflection {
+		// errors other than a missing service are left to the reflection client to report
+		reflection, err = detectReflection(timeoutCtx, clientConn)
+		switch {
+		case err == ErrReflectionUnavailable && fileSource == nil:
+			descSource = unavailableSource{err: err}
+		case err == ErrReflectionUnavailable:
+			// local files are enough to describe services
+		default:
+			refClient = newReflectionClient(ctx, clientConn, reflection)
+			descSource = grpcurl.DescriptorSourceFromServer(ctx, refClient)
+			if fileSource != nil {
+				descSource = compositeSource{reflection: descSource, file: fileSource}
+			}
 		}
-		clientConn = r.clientConn
 	}
-	refClient := grpcreflect.NewClientV1Alpha(ctx, reflectpb.NewServerReflectionClient(clientConn))
-	descSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
 	return &Wrapper{
 		cc:         clientConn,
 		refClient:  refClient,
 		descSource: descSource,
 		reqCancel:  nil,
 		Target:     target,
+		Reflection: reflection,
+		localFiles: fileSource != nil,
 	}, nil
 }
 
-func (g *Wrapper) ListServices() <-chan TypeAndError[[]string] {
-	resultChan := make(chan TypeAndError[[]string])
+// Source tells where services are described from, e.g. "reflection v1", the error
+// reports why they can not be described.
+func (g *Wrapper) Source() (string, error) {
+	if us, ok := g.descSource.(unavailableSource); ok {
+		return "", us.err
+	}
+	reflection := ""
+	if g.Reflection != "" {
+		version := strings.TrimSuffix(strings.TrimPrefix(g.Reflection, "grpc.reflection."), ".ServerReflection")
+		reflection = "reflection " + version
+	}
+	switch {
+	case g.localFiles && reflection != "":
+		return "local files and " + reflection, nil
+	case g.localFiles:
+		return "local files", nil
+	}
+	return reflection, nil
+}
+
+func newTLSConfig(opts Opts) (*tls.Config, error) {
+	tlsConfig, err := grpcurl.ClientTLSConfig(opts.InsecureSkipVerify, opts.CACert, opts.Cert, opts.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to configure TLS")
+	}
+	tlsConfig.ServerName = opts.ServerName
+	return tlsConfig, nil
+}
+
+func newFileSource(opts Opts) (grpcurl.DescriptorSource, error) {
+	switch {
+	case len(opts.ProtoFiles) > 0:
+		descSource, err := grpcurl.DescriptorSourceFromProtoFiles(opts.ImportPaths, opts.ProtoFiles...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process proto source files")
+		}
+		return descSource, nil
+	case len(opts.Protosets) > 0:
+		descSource, err := grpcurl.DescriptorSourceFromProtoSets(opts.Protosets...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process protoset files")
+		}
+		return descSource, nil
+	}
+	return nil, nil
+}
+
+func (g *Wrapper) ListServices() <-chan TypeAndError[[]ServiceInfo] {
+	resultChan := make(chan TypeAndError[[]ServiceInfo])
 	go func() {
 		defer close(resultChan)
-		services, err := grpcurl.ListServices(g.descSource)
-		resultChan <- TypeAndError[[]string]{Result: services, Err: err}
+		services, err := g.listServices()
+		resultChan <- TypeAndError[[]ServiceInfo]{Result: services, Err: err}
 	}()
 	return resultChan
 }
 
-func (g *Wrapper) ListMethods(service string) <-chan TypeAndError[[]string] {
-	resultChan := make(chan TypeAndError[[]string])
+func (g *Wrapper) listServices() ([]ServiceInfo, error) {
+	names, err := grpcurl.ListServices(g.descSource)
+	if err != nil {
+		return nil, err
+	}
+	services := make([]ServiceInfo, 0, len(names))
+	for _, name := range names {
+		service := ServiceInfo{Name: name}
+		// comments are optional, a service failing to resolve is reported when it is opened
+		if dsc, err := g.descSource.FindSymbol(name); err == nil {
+			service.Comment = comment(dsc)
+		}
+		services = append(services, service)
+	}
+	return services, nil
+}
+
+func (g *Wrapper) ListMethods(service string) <-chan TypeAndError[[]MethodInfo] {
+	resultChan := make(chan TypeAndError[[]MethodInfo])
 	go func() {
 		defer close(resultChan)
-		methods, err := grpcurl.ListMethods(g.descSource, service)
-		resultChan <- TypeAndError[[]string]{Result: methods, Err: err}
+		methods, err := g.listMethods(service)
+		resultChan <- TypeAndError[[]MethodInfo]{Result: methods, Err: err}
 	}()
 	return resultChan
 }
 
+func (g *Wrapper) listMethods(service string) ([]MethodInfo, error) {
+	dsc, err := g.descSource.FindSymbol(service)
+	if err != nil {
+		return nil, err
+	}
+	serviceDsc, ok := dsc.(*desc.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	methods := []MethodInfo{}
+	for _, method := range serviceDsc.GetMethods() {
+		methods = append(methods, MethodInfo{
+			Name:            method.GetFullyQualifiedName(),
+			Comment:         comment(method),
+			InputType:       method.GetInputType().GetFullyQualifiedName(),
+			OutputType:      method.GetOutputType().GetFullyQualifiedName(),
+			ClientStreaming: method.IsClientStreaming(),
+			ServerStreaming: method.IsServerStreaming(),
+		})
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	return methods, nil
+}
+
+// Kind tells how the method streams messages.
+func (m MethodInfo) Kind() string {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return "bidi streaming"
+	case m.ClientStreaming:
+		return "client streaming"
+	case m.ServerStreaming:
+		return "server streaming"
+	}
+	return "unary"
+}
+
 type MessageWrapper struct {
 	Msg protoreflect.Message
 }
@@ -167,19 +355,22 @@ func (mw MessageWrapper) ProtoReflect() protoreflect.Message {
 	return mw.Msg
 }
 
-func (g *Wrapper) getInDescription(method string) (string, string, error) {
+func (g *Wrapper) getInDescription(method string) (InDesc, error) {
 	dsc, err := g.descSource.FindSymbol(method)
 	if err != nil {
-		return "", "", err
+		return InDesc{}, err
 	}
 	methodDsc, ok := dsc.(*desc.MethodDescriptor)
 	if !ok {
-		return "", "", fmt.Errorf("not a method")
+		return InDesc{}, fmt.Errorf("not a method")
 	}
 	inType := methodDsc.GetInputType()
-	inDescText, err := grpcurl.GetDescriptorText(inType, g.descSource)
+	inDescText, err := describe(inType)
 	if err != nil {
-		return "", "", err
+		return InDesc{}, err
+	}
+	if methodComment := comment(methodDsc); methodComment != "" {
+		inDescText = commentLines(methodComment) + "\n\n" + inDescText
 	}
 	protoMsg := grpcurl.MakeTemplate(inType)
 	example, err := ProtoJSONMarshaler.MarshalToString(protoMsg)
@@ -187,20 +378,37 @@ func (g *Wrapper) getInDescription(method string) (string, string, error) {
 		example = "{}"
 	}
 
-	return inDescText, example, nil
+	return InDesc{
+		Desc:            inDescText,
+		Example:         example,
+		ClientStreaming: methodDsc.IsClientStreaming(),
+	}, nil
 }
 
 func (g *Wrapper) GetInputDescription(method string) <-chan InDesc {
 	resultChan := make(chan InDesc)
 	go func() {
 		defer close(resultChan)
-		desc, example, err := g.getInDescription(method)
-		resultChan <- InDesc{Desc: desc, Example: example, Err: err}
+		inDesc, err := g.getInDescription(method)
+		inDesc.Err = err
+		resultChan <- inDesc
 	}()
 	return resultChan
 }
 
-func (g *Wrapper) Invoke(method string, request string) (<-chan Event, error) {
+func (g *Wrapper) DescribeSymbol(name string) <-chan TypeAndError[SymbolInfo] {
+	resultChan := make(chan TypeAndError[SymbolInfo])
+	go func() {
+		defer close(resultChan)
+		info, err := describeSymbol(g.descSource, name)
+		resultChan <- TypeAndError[SymbolInfo]{Result: info, Err: err}
+	}()
+	return resultChan
+}
+
+// Invoke calls a method with a single request, the call is cancelled after
+// the timeout unless it is zero.
+func (g *Wrapper) Invoke(method string, headers []string, request string, timeout time.Duration) (<-chan Event, error) {
 	options := grpcurl.FormatOptions{
 		EmitJSONDefaultFields: false,
 		IncludeTextSeparator:  false,
@@ -210,20 +418,93 @@ func (g *Wrapper) Invoke(method string, request string) (<-chan Event, error) {
 	if err != nil {
 		return nil, err
 	}
-	resultChan := make(chan Event, 10)
+	ctx, cancel := g.newCallContext(timeout)
+	return g.invoke(ctx, cancel, method, headers, requestFormatter.Next), nil
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// InvokeStream starts a call whose request messages are supplied one by one
+// through the returned Stream until it is half-closed.
+func (g *Wrapper) InvokeStream(method string, headers []string, timeout time.Duration) (*Stream, <-chan Event) {
+	ctx, cancel := g.newCallContext(timeout)
+	stream := &Stream{
+		requests: make(chan string),
+		done:     ctx.Done(),
+	}
+	resolver := grpcurl.AnyResolverFromDescriptorSource(g.descSource)
+	supplier := func(m proto.Message) error {
+		select {
+		case request, ok := <-stream.requests:
+			if !ok {
+				return io.EOF
+			}
+			return grpcurl.NewJSONRequestParser(strings.NewReader(request), resolver).Next(m)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return stream, g.invoke(ctx, cancel, method, headers, supplier)
+}
+
+func (g *Wrapper) newCallContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	// only one call at a time, a previous stream must not outlive its view
+	g.CancelInvoke()
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		g.reqCancel = cancel
+		return ctx, cancel
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	g.reqCancel = cancel
-	h := &gRPCEventHandler{c: resultChan}
+	return ctx, cancel
+}
+
+func (g *Wrapper) invoke(ctx context.Context, cancel context.CancelFunc, method string, headers []string, supplier grpcurl.RequestSupplier) <-chan Event {
+	resultChan := make(chan Event, 10)
+	h := &gRPCEventHandler{c: resultChan, marshaler: g.newMarshaler()}
 	go func() {
-		err := grpcurl.InvokeRPC(ctx, g.descSource, g.cc, method, []string{}, h, requestFormatter.Next)
-		if err != nil {
+		defer cancel()
+		err := grpcurl.InvokeRPC(ctx, g.descSource, g.cc, method, headers, h, supplier)
+		if err != nil && ctx.Err() != nil {
+			// the deadline or cancellation hit before the server replied with a status
+			h.OnReceiveTrailers(status.FromContextError(ctx.Err()), nil)
+		} else if err != nil {
 			resultChan <- Event{Type: EventError, Err: err}
-			cancel()
 			close(resultChan)
 		}
 	}()
-	return resultChan, nil
+	return resultChan
+}
+
+// newMarshaler makes a JSON marshaler that resolves Any messages through the descriptor source.
+func (g *Wrapper) newMarshaler() *jsonpb.Marshaler {
+	marshaler := *ProtoJSONMarshaler
+	marshaler.AnyResolver = grpcurl.AnyResolverFromDescriptorSourceWithFallback(g.descSource)
+	return &marshaler
+}
+
+// Send blocks until the message is handed over to the call.
+func (s *Stream) Send(request string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return fmt.Errorf("stream is half-closed")
+	}
+	select {
+	case s.requests <- request:
+		return nil
+	case <-s.done:
+		return fmt.Errorf("stream is finished")
+	}
+}
+
+// CloseSend half-closes the stream, the server still can send responses.
+func (s *Stream) CloseSend() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.requests)
+	}
 }
 
 func (g *Wrapper) CancelInvoke() {
@@ -233,8 +514,21 @@ func (g *Wrapper) CancelInvoke() {
 	}
 }
 
+// State returns the connectivity state of the connection.
+func (g *Wrapper) State() connectivity.State {
+	return g.cc.GetState()
+}
+
+// WaitForStateChange blocks until the state of the connection differs from
+// the given one or the context is done.
+func (g *Wrapper) WaitForStateChange(ctx context.Context, state connectivity.State) bool {
+	return g.cc.WaitForStateChange(ctx, state)
+}
+
 func (g *Wrapper) Close() {
-	g.refClient.Reset()
+	if g.refClient != nil {
+		g.refClient.Reset()
+	}
 	g.cc.Close()
 }
 
@@ -244,15 +538,28 @@ func (h *gRPCEventHandler) OnResolveMethod(_ *desc.MethodDescriptor) {
 func (h *gRPCEventHandler) OnSendHeaders(metadata.MD) {
 	h.c <- Event{Type: HeadersSent}
 }
-func (h *gRPCEventHandler) OnReceiveHeaders(metadata.MD) {
-	h.c <- Event{Type: HeadersReceived}
+func (h *gRPCEventHandler) OnReceiveHeaders(md metadata.MD) {
+	h.c <- Event{Type: HeadersReceived, Payload: md}
 }
 func (h *gRPCEventHandler) OnReceiveResponse(m proto.Message) {
-	responseJSON, err := ProtoJSONMarshaler.MarshalToString(m)
+	responseJSON, err := h.marshaler.MarshalToString(m)
 
-	h.c <- Event{Type: ResponseReceived, Payload: responseJSON, Err: err}
+	h.c <- Event{Type: ResponseReceived, Payload: responseJSON, Err: err, Time: time.Now()}
 }
-func (h *gRPCEventHandler) OnReceiveTrailers(s *status.Status, _ metadata.MD) {
-	h.c <- Event{Type: ReceivedTrailers, Payload: s.Code().String()}
+func (h *gRPCEventHandler) OnReceiveTrailers(s *status.Status, md metadata.MD) {
+	details := []string{}
+	for _, detail := range s.Proto().GetDetails() {
+		detailJSON, err := h.marshaler.MarshalToString(detail)
+		if err != nil {
+			detailJSON = fmt.Sprintf("%s: %v", detail.GetTypeUrl(), err)
+		}
+		details = append(details, detailJSON)
+	}
+	h.c <- Event{Type: ReceivedTrailers, Payload: CallStatus{
+		Code:     s.Code().String(),
+		Message:  s.Message(),
+		Details:  details,
+		Trailers: md,
+	}}
 	close(h.c)
 }
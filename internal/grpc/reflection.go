@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ReflectionV1      = "grpc.reflection.v1.ServerReflection"
+	ReflectionV1Alpha = "grpc.reflection.v1alpha.ServerReflection"
+)
+
+var ErrReflectionUnavailable = errors.New(
+	"server does not expose " + ReflectionV1 + " nor " + ReflectionV1Alpha + ", use -proto or -protoset files",
+)
+
+// detectReflection returns the newest reflection service exposed by the server.
+func detectReflection(ctx context.Context, cc *grpc.ClientConn) (string, error) {
+	for _, service := range []string{ReflectionV1, ReflectionV1Alpha} {
+		err := probeReflection(ctx, cc, service)
+		if err == nil {
+			return service, nil
+		}
+		if status.Code(err) != codes.Unimplemented {
+			return "", err
+		}
+	}
+	return "", ErrReflectionUnavailable
+}
+
+// probeReflection lists services through the given reflection service.
+// Messages of v1 and v1alpha are wire compatible, so v1alpha types are used for both.
+func probeReflection(ctx context.Context, cc *grpc.ClientConn, service string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	streamDesc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+	stream, err := cc.NewStream(ctx, streamDesc, "/"+service+"/ServerReflectionInfo")
+	if err != nil {
+		return err
+	}
+	req := &reflectpb.ServerReflectionRequest{
+		MessageRequest: &reflectpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}
+	// on io.EOF the actual error is returned by RecvMsg
+	if err := stream.SendMsg(req); err != nil && err != io.EOF {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return stream.RecvMsg(&reflectpb.ServerReflectionResponse{})
+}
+
+func newReflectionClient(ctx context.Context, cc *grpc.ClientConn, service string) *grpcreflect.Client {
+	if service == ReflectionV1Alpha {
+		return grpcreflect.NewClientV1Alpha(ctx, reflectpb.NewServerReflectionClient(cc))
+	}
+	// prefers v1 and falls back to v1alpha by itself
+	return grpcreflect.NewClientAuto(ctx, cc)
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"sort"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+)
+
+// compositeSource asks the server reflection first and falls back to local
+// descriptors for symbols the server does not know about.
+type compositeSource struct {
+	reflection grpcurl.DescriptorSource
+	file       grpcurl.DescriptorSource
+}
+
+func (cs compositeSource) ListServices() ([]string, error) {
+	services, err := cs.reflection.ListServices()
+	if err != nil {
+		return nil, err
+	}
+	fileServices, err := cs.file.ListServices()
+	if err != nil {
+		return services, nil
+	}
+	known := make(map[string]bool, len(services))
+	for _, service := range services {
+		known[service] = true
+	}
+	for _, service := range fileServices {
+		if !known[service] {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+func (cs compositeSource) FindSymbol(fullyQualifiedName string) (desc.Descriptor, error) {
+	dsc, err := cs.reflection.FindSymbol(fullyQualifiedName)
+	if err == nil {
+		return dsc, nil
+	}
+	return cs.file.FindSymbol(fullyQualifiedName)
+}
+
+func (cs compositeSource) AllExtensionsForType(typeName string) ([]*desc.FieldDescriptor, error) {
+	exts, err := cs.reflection.AllExtensionsForType(typeName)
+	if err != nil {
+		return cs.file.AllExtensionsForType(typeName)
+	}
+	fileExts, err := cs.file.AllExtensionsForType(typeName)
+	if err != nil {
+		return exts, nil
+	}
+	// extensions known by the server take precedence
+	tags := make(map[int32]bool, len(exts))
+	for _, ext := range exts {
+		tags[ext.GetNumber()] = true
+	}
+	for _, ext := range fileExts {
+		if !tags[ext.GetNumber()] {
+			exts = append(exts, ext)
+		}
+	}
+	return exts, nil
+}
+
+// unavailableSource reports why services can not be described.
+type unavailableSource struct {
+	err error
+}
+
+func (us unavailableSource) ListServices() ([]string, error) {
+	return nil, us.err
+}
+
+func (us unavailableSource) FindSymbol(string) (desc.Descriptor, error) {
+	return nil, us.err
+}
+
+func (us unavailableSource) AllExtensionsForType(string) ([]*desc.FieldDescriptor, error) {
+	return nil, us.err
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"time"
+)
+
+const (
+	historyPrefix = "history:"
+	// MaxHistory is the number of requests kept per method.
+	MaxHistory = 50
+)
+
+// HistoryEntry is a sent request with its outcome.
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	Payload string    `json:"payload,omitempty"`
+	// Messages are the messages sent by a client-streaming or bidi call.
+	Messages []string `json:"messages,omitempty"`
+	// Rendered and RenderedMessages are the sent payloads when they differ from the
+	// edited ones, i.e. with the placeholders resolved.
+	Rendered         string        `json:"rendered,omitempty"`
+	RenderedMessages []string      `json:"rendered_messages,omitempty"`
+	Headers          []string      `json:"headers,omitempty"`
+	Deadline         string        `json:"deadline,omitempty"`
+	Status           string        `json:"status"`
+	Latency          time.Duration `json:"latency"`
+}
+
+// AddHistory puts the entry on top of the method history, the oldest entries are dropped.
+func (s *Store) AddHistory(method string, entry HistoryEntry) error {
+	history, err := s.History(method)
+	if err != nil {
+		// a broken history is replaced rather than blocking new entries
+		history = nil
+	}
+	history = append([]HistoryEntry{entry}, history...)
+	if len(history) > MaxHistory {
+		history = history[:MaxHistory]
+	}
+	s.Set(historyPrefix+method, history)
+	return err
+}
+
+// History returns the requests of the method, the newest first.
+func (s *Store) History(method string) ([]HistoryEntry, error) {
+	history := []HistoryEntry{}
+	if _, err := s.Decode(historyPrefix+method, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
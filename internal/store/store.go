@@ -12,7 +12,10 @@ import (
 	"github.com/pkg/errors"
 )
 
-const appName = "jordi"
+const (
+	appName    = "jordi"
+	globalName = "global"
+)
 
 type (
 	Value any
@@ -26,7 +29,16 @@ type (
 
 func New(name string) *Store {
 	cacheFilePath, _ := getCacheFilePath(name)
+	return newStore(cacheFilePath)
+}
+
+// NewGlobal opens the store of data shared by all targets.
+func NewGlobal() *Store {
+	cacheFilePath, _ := xdg.CacheFile(fmt.Sprintf("%s/%s.json", appName, globalName))
+	return newStore(cacheFilePath)
+}
 
+func newStore(cacheFilePath string) *Store {
 	store := &Store{
 		filepath: cacheFilePath,
 		data:     make(map[string]Value),
@@ -57,6 +69,24 @@ func (s *Store) Get(key string) Value {
 	return s.data[key]
 }
 
+// Decode unmarshals the value of the key into dest, it reports whether the key exists.
+func (s *Store) Decode(key string, dest any) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	value, ok := s.data[key]
+	if !ok {
+		return false, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to encode value of '%s'", key)
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return true, errors.Wrapf(err, "failed to decode value of '%s'", key)
+	}
+	return true, nil
+}
+
 func (s *Store) Set(key string, value Value) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
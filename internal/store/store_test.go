@@ -1,8 +1,10 @@
 package store
 
 import (
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -51,3 +53,68 @@ func TestCorruptedFile(t *testing.T) {
 
 	assert.Nil(t, store.Get("key1"))
 }
+
+func TestDecode(t *testing.T) {
+	assert.NoError(t, clearCache(testFileName))
+
+	type value struct {
+		Name  string   `json:"name"`
+		Items []string `json:"items"`
+	}
+
+	store := New(testFileName)
+	store.Set("key1", value{Name: "foo", Items: []string{"a", "b"}})
+
+	var decoded value
+	found, err := store.Decode("key1", &decoded)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, value{Name: "foo", Items: []string{"a", "b"}}, decoded)
+
+	assert.NoError(t, store.Flush())
+	store = New(testFileName)
+
+	decoded = value{}
+	found, err = store.Decode("key1", &decoded)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, value{Name: "foo", Items: []string{"a", "b"}}, decoded)
+
+	found, err = store.Decode("missing", &decoded)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestHistory(t *testing.T) {
+	assert.NoError(t, clearCache(testFileName))
+
+	store := New(testFileName)
+	history, err := store.History("svc.Method")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	startedAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < MaxHistory+5; i++ {
+		assert.NoError(t, store.AddHistory("svc.Method", HistoryEntry{
+			Time:    startedAt.Add(time.Duration(i) * time.Second),
+			Payload: fmt.Sprintf(`{"n": %d}`, i),
+			Status:  "OK",
+			Latency: time.Duration(i) * time.Millisecond,
+		}))
+	}
+	assert.NoError(t, store.AddHistory("svc.Other", HistoryEntry{Payload: "{}", Status: "NotFound"}))
+	assert.NoError(t, store.Flush())
+
+	store = New(testFileName)
+	history, err = store.History("svc.Method")
+	assert.NoError(t, err)
+	assert.Len(t, history, MaxHistory)
+	assert.Equal(t, fmt.Sprintf(`{"n": %d}`, MaxHistory+4), history[0].Payload)
+	assert.Equal(t, startedAt.Add(time.Duration(MaxHistory+4)*time.Second), history[0].Time)
+	assert.Equal(t, time.Duration(MaxHistory+4)*time.Millisecond, history[0].Latency)
+	assert.Equal(t, `{"n": 5}`, history[MaxHistory-1].Payload)
+
+	history, err = store.History("svc.Other")
+	assert.NoError(t, err)
+	assert.Equal(t, []HistoryEntry{{Payload: "{}", Status: "NotFound"}}, history)
+}
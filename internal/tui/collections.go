@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/profx5/jordi/internal/collection"
+	"github.com/profx5/jordi/internal/store"
+)
+
+const renameInputHeight = 1
+
+var (
+	collectionNameStyle = lipgloss.NewStyle().PaddingLeft(2).Bold(true)
+	savedRequestStyle   = lipgloss.NewStyle().PaddingLeft(4)
+	savedSelectedStyle  = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	previewStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false, false)
+)
+
+type (
+	CollectionsKeyMap struct {
+		Load   key.Binding
+		Up     key.Binding
+		Down   key.Binding
+		Rename key.Binding
+		Delete key.Binding
+	}
+	// CollectionsView browses the saved requests of all collections.
+	CollectionsView struct {
+		keyMap      CollectionsKeyMap
+		commands    *Commands
+		input       textinput.Model
+		preview     viewport.Model
+		title       TitleView
+		help        HelpView
+		collections []collection.Collection
+		items       []savedItem
+		selected    int
+		renaming    bool
+		// confirmDelete is set by the first delete key press
+		confirmDelete bool
+
+		width, height int
+	}
+	savedItem struct {
+		collection string
+		request    collection.Request
+	}
+)
+
+func DefaultCollectionsKeyMap() CollectionsKeyMap {
+	load := key.NewBinding(key.WithKeys("enter"))
+	load.SetHelp(`enter`, "load")
+
+	up := key.NewBinding(key.WithKeys("up"))
+	up.SetHelp(`↑`, "up")
+
+	down := key.NewBinding(key.WithKeys("down"))
+	down.SetHelp(`↓`, "down")
+
+	rename := key.NewBinding(key.WithKeys("r"))
+	rename.SetHelp(`r`, "rename")
+
+	remove := key.NewBinding(key.WithKeys("d"))
+	remove.SetHelp(`d`, "delete")
+
+	return CollectionsKeyMap{Load: load, Up: up, Down: down, Rename: rename, Delete: remove}
+}
+
+func (c CollectionsKeyMap) Bindings() []key.Binding {
+	return []key.Binding{c.Load, c.Up, c.Down, c.Rename, c.Delete}
+}
+
+func NewCollectionsView(commands *Commands) *CollectionsView {
+	input := textinput.New()
+	input.Prompt = "Rename to: "
+
+	keyMap := DefaultCollectionsKeyMap()
+	return &CollectionsView{
+		keyMap:   keyMap,
+		commands: commands,
+		input:    input,
+		preview:  viewport.New(0, 0),
+		title:    NewTitleView("Collections"),
+		help:     NewHelpView(keyMap),
+	}
+}
+
+func (c *CollectionsView) Init() tea.Cmd {
+	return nil
+}
+
+func (c *CollectionsView) selectedItem() (savedItem, bool) {
+	if c.selected < len(c.items) {
+		return c.items[c.selected], true
+	}
+	return savedItem{}, false
+}
+
+func (c *CollectionsView) refreshPreview() {
+	item, ok := c.selectedItem()
+	if !ok {
+		c.preview.SetContent(responseHeaderStyle.Render("No saved requests in " + c.commands.collections.Path()))
+		return
+	}
+	request := item.request
+	text := requestText(store.HistoryEntry{Payload: string(request.Payload), Headers: request.Headers, Deadline: request.Deadline})
+	c.preview.SetContent(responseHeaderStyle.Render(request.Method) + "\n\n" + text)
+	c.preview.GotoTop()
+}
+
+func (c *CollectionsView) setCollections(collections []collection.Collection) {
+	c.collections = collections
+	c.items = nil
+	for _, col := range collections {
+		for _, request := range col.Requests {
+			c.items = append(c.items, savedItem{collection: col.Name, request: request})
+		}
+	}
+	if c.selected >= len(c.items) {
+		c.selected = len(c.items) - 1
+	}
+	if c.selected < 0 {
+		c.selected = 0
+	}
+	c.refreshPreview()
+}
+
+func (c *CollectionsView) stopRenaming() {
+	c.renaming = false
+	c.input.Blur()
+}
+
+func (c *CollectionsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if c.renaming {
+			if msg.Type != tea.KeyEnter {
+				var cmd tea.Cmd
+				c.input, cmd = c.input.Update(msg)
+				return c, cmd
+			}
+			c.stopRenaming()
+			item, ok := c.selectedItem()
+			if !ok {
+				return c, nil
+			}
+			return c, c.commands.RenameRequest(item.collection, item.request.Name, strings.TrimSpace(c.input.Value()))
+		}
+		confirmDelete := c.confirmDelete
+		c.confirmDelete = false
+		switch {
+		case key.Matches(msg, c.keyMap.Load):
+			if item, ok := c.selectedItem(); ok {
+				return c, func() tea.Msg { return ChosenRequest{Collection: item.collection, Request: item.request} }
+			}
+		case key.Matches(msg, c.keyMap.Up):
+			if c.selected > 0 {
+				c.selected--
+				c.refreshPreview()
+			}
+		case key.Matches(msg, c.keyMap.Down):
+			if c.selected < len(c.items)-1 {
+				c.selected++
+				c.refreshPreview()
+			}
+		case key.Matches(msg, c.keyMap.Rename):
+			if item, ok := c.selectedItem(); ok {
+				c.renaming = true
+				c.input.SetValue(item.request.Name)
+				c.input.CursorEnd()
+				c.input.Focus()
+				return c, textinput.Blink
+			}
+		case key.Matches(msg, c.keyMap.Delete):
+			item, ok := c.selectedItem()
+			if !ok {
+				return c, nil
+			}
+			if confirmDelete {
+				return c, tea.Batch(c.commands.DeleteRequest(item.collection, item.request.Name), c.commands.ClearStatusMsg())
+			}
+			c.confirmDelete = true
+			return c, c.commands.SetStatusMessage(
+				fmt.Sprintf("Press d again to delete %s", savedName(item.collection, item.request.Name)), StatusMsgError,
+			)
+		}
+		if confirmDelete {
+			return c, c.commands.ClearStatusMsg()
+		}
+	case ShowCollections:
+		c.setCollections(msg.Collections)
+	case Err:
+		// the method of the chosen request failed to load
+		return c, c.commands.SetStatusOK()
+	case Back:
+		c.stopRenaming()
+		c.confirmDelete = false
+	}
+	return c, nil
+}
+
+// listLines renders the collections with their requests and tells the line of the selected one.
+func (c *CollectionsView) listLines() ([]string, int) {
+	lines := []string{}
+	selectedLine, i := 0, 0
+	for _, col := range c.collections {
+		lines = append(lines, collectionNameStyle.Render(col.Name))
+		for _, request := range col.Requests {
+			text := fmt.Sprintf("%s  %s", request.Name, responseHeaderStyle.Render(getShortMethodName(request.Method)))
+			if i == c.selected {
+				selectedLine = len(lines)
+				lines = append(lines, savedSelectedStyle.MaxWidth(c.width).Render("│ "+text))
+			} else {
+				lines = append(lines, savedRequestStyle.MaxWidth(c.width).Render(text))
+			}
+			i++
+		}
+	}
+	return lines, selectedLine
+}
+
+func (c *CollectionsView) listHeight() int {
+	return (c.height - titleHeight - helpHeight) / 2
+}
+
+func (c *CollectionsView) listView() string {
+	lines, selectedLine := c.listLines()
+	height := c.listHeight()
+	// keep the selected request visible
+	offset := 0
+	if selectedLine >= height {
+		offset = selectedLine - height + 1
+	}
+	lines = lines[offset:]
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *CollectionsView) View() string {
+	views := []string{c.title.View(), c.listView()}
+	previewHeight := c.height - titleHeight - helpHeight - c.listHeight() - 1
+	if c.renaming {
+		views = append(views, lipgloss.NewStyle().PaddingLeft(2).Render(c.input.View()))
+		previewHeight -= renameInputHeight
+	}
+	c.preview.Width = c.width
+	c.preview.Height = previewHeight
+	views = append(views, previewStyle.Width(c.width).Render(c.preview.View()), c.help.View())
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
+}
+
+func (c *CollectionsView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	c.width, c.height = msg.Width, msg.Height
+	c.input.Width = msg.Width - len(c.input.Prompt) - 4
+	c.help.SetWidth(msg.Width)
+}
@@ -1,84 +1,557 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/profx5/jordi/internal/collection"
+	"github.com/profx5/jordi/internal/config"
 	"github.com/profx5/jordi/internal/grpc"
 	"github.com/profx5/jordi/internal/store"
+	"github.com/profx5/jordi/internal/vars"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
 )
 
-type Commands struct {
-	cancel chan struct{}
-	grpc   *grpc.Wrapper
-	store  *store.Store
-}
+const (
+	recentTargetsKey = "recent_targets"
+	maxRecentTargets = 10
+	// capturedPrefix keeps the values captured from responses per environment,
+	// capturesPrefix the rules of a method
+	capturedPrefix = "captured:"
+	capturesPrefix = "captures:"
+)
+
+var errNotConnected = errors.New("not connected, press f5 to reconnect")
 
-func NewCommands(grpc *grpc.Wrapper, store *store.Store) *Commands {
+type (
+	// Dialer connects to the target and loads its descriptor source.
+	Dialer   func(ctx context.Context, target config.Target) (*grpc.Wrapper, error)
+	Commands struct {
+		dial   Dialer
+		target config.Target
+		// grpc is nil until the connection is established
+		grpc *grpc.Wrapper
+		// store keeps requests of the target, global is shared by all targets
+		store       *store.Store
+		global      *store.Store
+		collections *collection.Dir
+		headers     []string
+		maxTime     time.Duration
+		// environment is the name of the selected environment, empty for none
+		environment  string
+		environments map[string]config.Environment
+	}
+	// historyRecord collects the sent request until the call gets its status.
+	historyRecord struct {
+		method string
+		entry  store.HistoryEntry
+		// store is the one of the target the request was sent to
+		store *store.Store
+	}
+	savedRequest struct {
+		Payload  string   `json:"payload"`
+		Headers  []string `json:"headers,omitempty"`
+		Deadline string   `json:"deadline,omitempty"`
+	}
+)
+
+func NewCommands(cfg config.Config, dial Dialer, store, global *store.Store) *Commands {
 	return &Commands{
-		grpc:   grpc,
-		cancel: make(chan struct{}),
-		store:  store,
+		dial:         dial,
+		target:       initialTarget(cfg),
+		store:        store,
+		global:       global,
+		collections:  collection.NewDir(cfg.CollectionsDir),
+		headers:      cfg.Headers,
+		maxTime:      cfg.MaxTime,
+		environment:  cfg.Environment,
+		environments: cfg.Environments,
 	}
 }
 
-func (c *Commands) Back() tea.Cmd {
-	return func() tea.Msg {
-		c.cancel <- struct{}{}
+// initialTarget is the target of the command line, named after the config file entry.
+func initialTarget(cfg config.Config) config.Target {
+	for _, target := range cfg.Targets {
+		if target.Same(config.Target{Address: cfg.Target, Plaintext: cfg.Plaintext}) {
+			return target
+		}
+	}
+	return config.Target{Address: cfg.Target, Plaintext: cfg.Plaintext}
+}
+
+func (c *Commands) Connect() tea.Cmd {
+	target := c.target
+	environment := c.environment
+	variables := c.variables()
+	return tea.Batch(func() tea.Msg {
+		// the address may have placeholders, the target keeps them to match the connection
+		dialed := target
+		address, err := vars.Render(target.Address, variables)
+		if err != nil {
+			return ConnectFailed{Target: target, Environment: environment, Error: err}
+		}
+		dialed.Address = address
+		g, err := c.dial(context.Background(), dialed)
+		if err != nil {
+			return ConnectFailed{Target: target, Environment: environment, Error: err}
+		}
+		return Connected{Target: target, Environment: environment, Wrapper: g}
+	}, c.SetConnectionState(nil, connectivity.Connecting))
+}
+
+// IsCurrent tells whether a connection dialed for the target with the environment
+// is the one of the current target and environment.
+func (c *Commands) IsCurrent(target config.Target, environment string) bool {
+	return target == c.target && (environment == c.environment || !c.addressHasPlaceholders())
+}
+
+func (c *Commands) addressHasPlaceholders() bool {
+	return strings.Contains(c.target.Address, "{{")
+}
+
+// SwitchTarget drops the current connection and connects to the target.
+func (c *Commands) SwitchTarget(target config.Target) tea.Cmd {
+	c.SetWrapper(nil)
+	c.store.Flush()
+	_, address := config.ParseTarget(target.Address)
+	c.store = store.New(address)
+	c.target = target
+	return c.Connect()
+}
+
+// RememberTarget puts the current target on top of the recent ones.
+func (c *Commands) RememberTarget() {
+	recent := []config.Target{c.target}
+	for _, target := range c.RecentTargets() {
+		if !target.Same(c.target) && len(recent) < maxRecentTargets {
+			recent = append(recent, target)
+		}
+	}
+	c.global.Set(recentTargetsKey, recent)
+}
+
+func (c *Commands) RecentTargets() []config.Target {
+	recent := []config.Target{}
+	if _, err := c.global.Decode(recentTargetsKey, &recent); err != nil {
+		return nil
+	}
+	return recent
+}
+
+// SetWrapper replaces the connection, the previous one is closed.
+func (c *Commands) SetWrapper(g *grpc.Wrapper) {
+	if c.grpc != nil {
 		c.grpc.CancelInvoke()
-		return Back{}
+		c.grpc.Close()
+	}
+	c.grpc = g
+}
+
+func (c *Commands) Close() {
+	c.SetWrapper(nil)
+	c.store.Flush()
+	c.global.Flush()
+}
+
+// variables are the ones of the environment and the ones captured from responses of the target,
+// the captured values are newer and take precedence.
+func (c *Commands) variables() map[string]string {
+	variables := map[string]string{}
+	for name, value := range c.environments[c.environment] {
+		variables[name] = value
+	}
+	for name, value := range c.captured(c.environment) {
+		variables[name] = value
+	}
+	return variables
+}
+
+// VariableSources lists the variables sorted by name, each one with the environment or
+// the capture it comes from.
+func (c *Commands) VariableSources() []string {
+	environment := c.environments[c.environment]
+	captured := c.captured(c.environment)
+	names := []string{}
+	for name := range environment {
+		if _, ok := captured[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range captured {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := make([]string, 0, len(names))
+	for _, name := range names {
+		_, isEnv := environment[name]
+		_, isCaptured := captured[name]
+		switch {
+		case isCaptured && isEnv:
+			sources = append(sources, fmt.Sprintf("%s (captured, overrides %s)", name, c.environment))
+		case isCaptured:
+			sources = append(sources, name+" (captured)")
+		default:
+			sources = append(sources, fmt.Sprintf("%s (%s)", name, c.environment))
+		}
+	}
+	return sources
+}
+
+// captured are the values captured from responses while the environment was selected.
+func (c *Commands) captured(environment string) map[string]string {
+	captured := map[string]string{}
+	if _, err := c.store.Decode(capturedPrefix+environment, &captured); err != nil {
+		return map[string]string{}
+	}
+	return captured
+}
+
+// ClearCaptured forgets the values captured with the selected environment, the rules are kept.
+func (c *Commands) ClearCaptured() {
+	c.store.Set(capturedPrefix+c.environment, map[string]string{})
+}
+
+// forgetCaptured drops the captured value of the variable in every environment.
+func (c *Commands) forgetCaptured(name string) {
+	for _, environment := range append(c.EnvironmentNames(), "") {
+		captured := c.captured(environment)
+		if _, ok := captured[name]; ok {
+			delete(captured, name)
+			c.store.Set(capturedPrefix+environment, captured)
+		}
+	}
+}
+
+// CaptureRules are the captures applied to the responses of the method.
+func (c *Commands) CaptureRules(method string) []vars.Capture {
+	rules := []vars.Capture{}
+	if _, err := c.store.Decode(capturesPrefix+method, &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
+// SetCaptureRule adds the rule of the text to the method, it replaces the rule of the same
+// variable. A variable name alone removes its rule together with the captured value.
+func (c *Commands) SetCaptureRule(method, text string) error {
+	name := strings.TrimSpace(text)
+	rule, err := vars.ParseCapture(text)
+	if err != nil && !vars.IsValidName(name) {
+		return err
+	}
+	if err == nil {
+		name = rule.Name
+	}
+	rules := []vars.Capture{}
+	for _, other := range c.CaptureRules(method) {
+		if other.Name != name {
+			rules = append(rules, other)
+		}
+	}
+	if err == nil {
+		rules = append(rules, rule)
+	} else {
+		c.forgetCaptured(name)
+	}
+	c.store.Set(capturesPrefix+method, rules)
+	return nil
+}
+
+// Capture keeps the values of the response selected by the rules of the method, it returns
+// the names of the captured variables and the error of the first failed rule.
+func (c *Commands) Capture(method, response string) ([]string, error) {
+	captured := c.captured(c.environment)
+	names := []string{}
+	var firstErr error
+	for _, rule := range c.CaptureRules(method) {
+		value, err := vars.Extract(response, rule.Path)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("capture of %s failed: %w", rule.Name, err)
+			}
+			continue
+		}
+		captured[rule.Name] = value
+		names = append(names, rule.Name)
+	}
+	c.store.Set(capturedPrefix+c.environment, captured)
+	return names, firstErr
+}
+
+// SetEnvironment selects the environment, it reports whether the address of the target depends on it.
+func (c *Commands) SetEnvironment(name string) bool {
+	c.environment = name
+	return c.addressHasPlaceholders()
+}
+
+// EnvironmentNames lists the configured environments sorted by name.
+func (c *Commands) EnvironmentNames() []string {
+	names := make([]string, 0, len(c.environments))
+	for name := range c.environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preview substitutes the variables into the payload and the metadata, the template
+// functions are left as written.
+func (c *Commands) Preview(payload string, headers []string) (string, []string, error) {
+	return resolveRequest(vars.Preview, c.variables(), payload, headers)
+}
+
+// PreviewTarget substitutes the variables into the target address.
+func (c *Commands) PreviewTarget() (string, error) {
+	return vars.Preview(c.target.Address, c.variables())
+}
+
+// CheckPayload validates the JSON of the payload with the placeholders resolved.
+func (c *Commands) CheckPayload(payload string) error {
+	resolved, err := vars.Render(payload, c.variables())
+	if err != nil {
+		return err
+	}
+	return checkJSON(resolved)
+}
+
+func resolveRequest(render func(string, map[string]string) (string, error), variables map[string]string, payload string, headers []string) (string, []string, error) {
+	resolved, err := render(payload, variables)
+	if err != nil {
+		return "", nil, err
+	}
+	metadata, err := render(strings.Join(headers, "\n"), variables)
+	if err != nil {
+		return "", nil, err
+	}
+	resolvedHeaders, err := parseHeaders(metadata)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := checkBinaryHeaders(resolvedHeaders); err != nil {
+		return "", nil, err
+	}
+	return resolved, resolvedHeaders, nil
+}
+
+func (c *Commands) SetConnectionState(g *grpc.Wrapper, state connectivity.State) tea.Cmd {
+	return func() tea.Msg {
+		return ConnectionState{wrapper: g, State: state}
+	}
+}
+
+// WatchConnection reports the next state change of the connection.
+func (c *Commands) WatchConnection(g *grpc.Wrapper, state connectivity.State) tea.Cmd {
+	return func() tea.Msg {
+		g.WaitForStateChange(context.Background(), state)
+		return ConnectionState{wrapper: g, State: g.State()}
 	}
 }
 
 func (c *Commands) LoadServices() tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
 	return tea.Batch(func() tea.Msg {
-		select {
-		case <-c.cancel:
-			return nil
-		case r := <-c.grpc.ListServices():
-			if r.Err != nil {
-				return Err{Error: r.Err}
-			}
-			return ShowServicesList{Services: r.Result}
+		r := <-g.ListServices()
+		if r.Err != nil {
+			return Err{Error: r.Err}
 		}
+		return ShowServicesList{Services: r.Result}
 	}, c.SetStatusLoading())
 }
 
 func (c *Commands) LoadMethods(service string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
 	return tea.Batch(func() tea.Msg {
-		select {
-		case <-c.cancel:
-			return nil
-		case r := <-c.grpc.ListMethods(service):
-			if r.Err != nil {
-				return Err{Error: r.Err}
-			}
-			return ShowMethodsList{Service: service, Methods: r.Result}
+		r := <-g.ListMethods(service)
+		if r.Err != nil {
+			return Err{Error: r.Err}
 		}
+		return ShowMethodsList{Service: service, Methods: r.Result}
 	}, c.SetStatusLoading())
 }
 
 func (c *Commands) LoadMethodMetadata(method string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	st := c.store
 	return tea.Batch(func() tea.Msg {
-		select {
-		case <-c.cancel:
-			return nil
-		case description := <-c.grpc.GetInputDescription(method):
-			if description.Err != nil {
-				return Err{Error: description.Err}
-			}
-			example := description.Example
-			cached := c.store.Get(method)
-			if cached != nil {
-				example = cached.(string)
-			}
-			return ShowRequester{
-				Method:        method,
-				InDescription: description.Desc,
-				InExample:     example,
+		description := <-g.GetInputDescription(method)
+		if description.Err != nil {
+			return Err{Error: description.Err}
+		}
+		return c.showRequester(st, method, description)
+	}, c.SetStatusLoading())
+}
+
+// OpenSavedRequest shows the request editor of the method filled with the saved request.
+func (c *Commands) OpenSavedRequest(name string, request collection.Request) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	st := c.store
+	return tea.Batch(func() tea.Msg {
+		description := <-g.GetInputDescription(request.Method)
+		if description.Err != nil {
+			return Err{Error: description.Err}
+		}
+		requester := c.showRequester(st, request.Method, description)
+		requester.InExample = string(request.Payload)
+		requester.Headers = mergeHeaders(request.Headers, c.headers)
+		requester.Deadline = request.Deadline
+		requester.SavedAs = savedName(name, request.Name)
+		return requester
+	}, c.SetStatusLoading())
+}
+
+func (c *Commands) LoadCollections() tea.Cmd {
+	return func() tea.Msg {
+		collections, err := c.collections.List()
+		if err != nil {
+			return Err{Error: err}
+		}
+		return ShowCollections{Collections: collections}
+	}
+}
+
+func (c *Commands) SaveRequest(name string, request collection.Request) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.collections.Save(name, request); err != nil {
+			return Err{Error: err}
+		}
+		return RequestSaved{SavedAs: savedName(name, request.Name)}
+	}
+}
+
+func (c *Commands) RenameRequest(name, oldName, newName string) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.collections.Rename(name, oldName, newName); err != nil {
+			return Err{Error: err}
+		}
+		return c.LoadCollections()()
+	}
+}
+
+func (c *Commands) DeleteRequest(name, requestName string) tea.Cmd {
+	return func() tea.Msg {
+		if err := c.collections.Delete(name, requestName); err != nil {
+			return Err{Error: err}
+		}
+		return c.LoadCollections()()
+	}
+}
+
+func (c *Commands) DescribeSymbol(name string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	return tea.Batch(func() tea.Msg {
+		r := <-g.DescribeSymbol(name)
+		if r.Err != nil {
+			return Err{Error: r.Err}
+		}
+		return SymbolDescribed{Info: r.Result}
+	}, c.SetStatusLoading())
+}
+
+// LoadAllMethods lists the methods of every service, services failing to resolve are skipped.
+func (c *Commands) LoadAllMethods() tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	return tea.Batch(func() tea.Msg {
+		services := <-g.ListServices()
+		if services.Err != nil {
+			return Err{Error: services.Err}
+		}
+		all := []grpc.MethodInfo{}
+		failed := []string{}
+		var firstErr error
+		for _, service := range services.Result {
+			methods := <-g.ListMethods(service.Name)
+			if methods.Err != nil {
+				failed = append(failed, service.Name)
+				if firstErr == nil {
+					firstErr = methods.Err
+				}
+				continue
 			}
+			all = append(all, methods.Result...)
+		}
+		if firstErr != nil {
+			return AllMethodsLoaded{Methods: all, Err: fmt.Errorf("skipped %s: %w", strings.Join(failed, ", "), firstErr)}
+		}
+		return AllMethodsLoaded{Methods: all}
+	}, c.SetStatusLoading())
+}
+
+// ReloadMethod opens the method on a new connection, the services list is
+// shown instead when the server does not have it.
+func (c *Commands) ReloadMethod(method string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	st := c.store
+	return tea.Batch(func() tea.Msg {
+		description := <-g.GetInputDescription(method)
+		if description.Err == nil {
+			return c.showRequester(st, method, description)
+		}
+		r := <-g.ListServices()
+		if r.Err != nil {
+			return Err{Error: r.Err}
+		}
+		return ShowServicesList{
+			Services:    r.Result,
+			Unavailable: fmt.Errorf("%s is not available on %s", method, g.Target),
 		}
 	}, c.SetStatusLoading())
 }
 
+// showRequester fills the editor with the request of the method saved in the store of the target.
+func (c *Commands) showRequester(st *store.Store, method string, description grpc.InDesc) ShowRequester {
+	example := description.Example
+	saved, found := loadRequest(st, method)
+	if found {
+		example = saved.Payload
+	}
+	return ShowRequester{
+		Method:          method,
+		InDescription:   description.Desc,
+		InExample:       example,
+		Headers:         mergeHeaders(saved.Headers, c.headers),
+		Deadline:        saved.Deadline,
+		ClientStreaming: description.ClientStreaming,
+	}
+}
+
+func loadRequest(st *store.Store, method string) (savedRequest, bool) {
+	// older versions stored only the payload
+	if payload, ok := st.Get(method).(string); ok {
+		return savedRequest{Payload: payload}, true
+	}
+	saved := savedRequest{}
+	found, err := st.Decode(method, &saved)
+	return saved, found && err == nil
+}
+
 func mapRespChanToMsg(ch <-chan grpc.Event) <-chan tea.Msg {
 	out := make(chan tea.Msg)
 	go func() {
@@ -89,12 +562,21 @@ func mapRespChanToMsg(ch <-chan grpc.Event) <-chan tea.Msg {
 			switch respPart.Type {
 			case grpc.EventError:
 				out <- Err{Error: respPart.Err}
+			case grpc.HeadersReceived:
+				headers := respPart.Payload.(metadata.MD)
+				out <- ReceivedHeaders{Headers: headers, ch: out}
 			case grpc.ResponseReceived:
 				response := respPart.Payload.(string)
-				out <- ReceivedResponse{Response: response, ch: out}
+				out <- ReceivedResponse{Response: response, ReceivedAt: respPart.Time, ch: out}
 			case grpc.ReceivedTrailers:
-				status := respPart.Payload.(string)
-				out <- ReceivedStatus{Status: status, ch: out}
+				status := respPart.Payload.(grpc.CallStatus)
+				out <- ReceivedStatus{
+					Status:   status.Code,
+					Message:  status.Message,
+					Details:  status.Details,
+					Trailers: status.Trailers,
+					ch:       out,
+				}
 			}
 		}
 		close(out)
@@ -102,19 +584,157 @@ func mapRespChanToMsg(ch <-chan grpc.Event) <-chan tea.Msg {
 	return out
 }
 
-func (c *Commands) SendRequest(method string, payload string) tea.Cmd {
+// callTimeout resolves the deadline of the request editor, -max-time is used when it is empty.
+func (c *Commands) callTimeout(deadline string) (time.Duration, error) {
+	timeout, err := parseDeadline(deadline)
+	if err != nil || strings.TrimSpace(deadline) != "" {
+		return timeout, err
+	}
+	return c.maxTime, nil
+}
+
+func (c *Commands) SendRequest(method string, headers []string, payload, deadline string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	// the target may be switched before the call starts, the request is kept for this one
+	st := c.store
+	variables := c.variables()
 	return func() tea.Msg {
-		err := checkJSON(payload)
+		resolved, resolvedHeaders, err := resolveRequest(vars.Render, variables, payload, headers)
+		if err != nil {
+			return Err{Error: err}
+		}
+		if err := checkJSON(resolved); err != nil {
+			return Err{Error: err}
+		}
+		timeout, err := c.callTimeout(deadline)
 		if err != nil {
 			return Err{Error: err}
 		}
 
-		ch, err := c.grpc.Invoke(method, payload)
+		startedAt := time.Now()
+		ch, err := g.Invoke(method, resolvedHeaders, resolved, timeout)
 		if err != nil {
 			return Err{Error: err}
 		}
-		c.store.Set(method, payload)
-		return ShowResponseView{mapRespChanToMsg(ch)}
+		st.Set(method, savedRequest{Payload: payload, Headers: headers, Deadline: deadline})
+		record := historyRecord{method: method, store: st, entry: store.HistoryEntry{
+			Time:     startedAt,
+			Payload:  payload,
+			Rendered: resolved,
+			Headers:  headers,
+			Deadline: deadline,
+		}}
+		return ShowResponseView{ch: mapRespChanToMsg(ch), StartedAt: startedAt, record: record}
+	}
+}
+
+func (c *Commands) OpenStream(method string, headers []string, deadline string) tea.Cmd {
+	g := c.grpc
+	if g == nil {
+		return c.notConnected()
+	}
+	st := c.store
+	variables := c.variables()
+	return func() tea.Msg {
+		_, resolvedHeaders, err := resolveRequest(vars.Render, variables, "", headers)
+		if err != nil {
+			return Err{Error: err}
+		}
+		timeout, err := c.callTimeout(deadline)
+		if err != nil {
+			return Err{Error: err}
+		}
+		startedAt := time.Now()
+		stream, ch := g.InvokeStream(method, resolvedHeaders, timeout)
+		record := historyRecord{method: method, store: st, entry: store.HistoryEntry{
+			Time:     startedAt,
+			Headers:  headers,
+			Deadline: deadline,
+		}}
+		return StreamOpened{Stream: stream, ch: mapRespChanToMsg(ch), StartedAt: startedAt, record: record}
+	}
+}
+
+// AddHistory completes the record with the call status and keeps it in the method history
+// of the target the request was sent to.
+func (c *Commands) AddHistory(record historyRecord, status string) tea.Cmd {
+	record.entry.Status = status
+	record.entry.Latency = time.Since(record.entry.Time)
+	// rendered payloads are kept only when placeholders changed them
+	if record.entry.Rendered == record.entry.Payload {
+		record.entry.Rendered = ""
+	}
+	if strings.Join(record.entry.RenderedMessages, "\n") == strings.Join(record.entry.Messages, "\n") {
+		record.entry.RenderedMessages = nil
+	}
+	if err := record.store.AddHistory(record.method, record.entry); err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	// the store of a previous target was flushed on the switch
+	if record.store != c.store {
+		if err := record.store.Flush(); err != nil {
+			return func() tea.Msg { return Err{Error: err} }
+		}
+	}
+	return nil
+}
+
+func (c *Commands) History(method string) ([]store.HistoryEntry, error) {
+	return c.store.History(method)
+}
+
+func (c *Commands) SendStreamMessages(stream *grpc.Stream, payloads []string) tea.Cmd {
+	variables := c.variables()
+	return func() tea.Msg {
+		rendered := make([]string, 0, len(payloads))
+		for _, payload := range payloads {
+			resolved, err := vars.Render(payload, variables)
+			if err == nil {
+				err = checkJSON(resolved)
+			}
+			if err != nil {
+				return StreamMessagesSent{Err: err}
+			}
+			rendered = append(rendered, resolved)
+		}
+		for i, payload := range rendered {
+			if err := stream.Send(payload); err != nil {
+				return StreamMessagesSent{Payloads: payloads[:i], Rendered: rendered[:i], Err: err}
+			}
+		}
+		return StreamMessagesSent{Payloads: payloads, Rendered: rendered}
+	}
+}
+
+func (c *Commands) CloseStream(stream *grpc.Stream) tea.Cmd {
+	return func() tea.Msg {
+		stream.CloseSend()
+		return StreamHalfClosed{}
+	}
+}
+
+func (c *Commands) CancelInvoke() tea.Cmd {
+	g := c.grpc
+	return func() tea.Msg {
+		if g != nil {
+			g.CancelInvoke()
+		}
+		return nil
+	}
+}
+
+func (c *Commands) notConnected() tea.Cmd {
+	return func() tea.Msg {
+		return Err{Error: errNotConnected}
+	}
+}
+
+func waitForMsg(sub <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-sub
 	}
 }
 
@@ -138,6 +758,18 @@ func (c *Commands) SetStatusMessage(msg string, st StatusMsgType) tea.Cmd {
 	}
 }
 
+func (c *Commands) StartElapsed(startedAt time.Time) tea.Cmd {
+	return func() tea.Msg {
+		return CallStarted{StartedAt: startedAt}
+	}
+}
+
+func (c *Commands) StopElapsed() tea.Cmd {
+	return func() tea.Msg {
+		return CallFinished{}
+	}
+}
+
 func (c *Commands) ClearStatusMsg() tea.Cmd {
 	return func() tea.Msg {
 		return ClearStatusMsg{}
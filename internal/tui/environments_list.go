@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/profx5/jordi/internal/config"
+)
+
+type (
+	EnvironmentsListKeyMap struct {
+		Enter key.Binding
+	}
+	EnvironmentsListItem struct {
+		// Name is empty for the item without an environment
+		Name      string
+		Variables config.Environment
+		Current   bool
+	}
+	EnvironmentsListView struct {
+		keyMap   EnvironmentsListKeyMap
+		commands *Commands
+		view     list.Model
+	}
+)
+
+func (i EnvironmentsListItem) FilterValue() string {
+	return i.Name
+}
+
+func (i EnvironmentsListItem) Title() string {
+	title := i.Name
+	if title == "" {
+		title = "none"
+	}
+	if i.Current {
+		return title + " (current)"
+	}
+	return title
+}
+
+func (i EnvironmentsListItem) Description() string {
+	if i.Name == "" {
+		return "only template functions and captured values"
+	}
+	names := make([]string, 0, len(i.Variables))
+	for name := range i.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%d variables: %s", len(names), strings.Join(names, ", "))
+}
+
+func NewEnvironmentsListView(commands *Commands) *EnvironmentsListView {
+	view := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	view.Title = "Environments"
+
+	return &EnvironmentsListView{
+		keyMap:   EnvironmentsListKeyMap{Enter: key.NewBinding(key.WithKeys("enter"))},
+		commands: commands,
+		view:     view,
+	}
+}
+
+func (m *EnvironmentsListView) Init() tea.Cmd {
+	return nil
+}
+
+func (m *EnvironmentsListView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keyMap.Enter) && m.view.FilterState() != list.Filtering {
+			if item, ok := m.view.SelectedItem().(EnvironmentsListItem); ok {
+				return m, func() tea.Msg { return ChosenEnvironment{Name: item.Name} }
+			}
+			return m, nil
+		}
+	case ShowEnvironments:
+		items := []list.Item{EnvironmentsListItem{Current: msg.Current == ""}}
+		selected := 0
+		for i, name := range msg.Names {
+			if name == msg.Current {
+				selected = i + 1
+			}
+			items = append(items, EnvironmentsListItem{Name: name, Variables: msg.Environments[name], Current: name == msg.Current})
+		}
+		m.view.ResetFilter()
+		cmds = append(cmds, m.view.SetItems(items))
+		m.view.Select(selected)
+	}
+	var cmd tea.Cmd
+	m.view, cmd = m.view.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *EnvironmentsListView) View() string {
+	return m.view.View()
+}
+
+func (m *EnvironmentsListView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	m.view.SetWidth(msg.Width)
+	m.view.SetHeight(msg.Height)
+}
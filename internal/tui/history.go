@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/profx5/jordi/internal/store"
+)
+
+const historyTimeFormat = "2006-01-02 15:04:05"
+
+const (
+	previewTab historyTab = iota
+	diffTab
+)
+
+var (
+	historyItemStyle     = lipgloss.NewStyle().PaddingLeft(4)
+	historySelectedStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	historyErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+	historyOKStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00"))
+	diffAddedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00"))
+	diffRemovedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+)
+
+type (
+	HistoryKeyMap struct {
+		Restore  key.Binding
+		Up       key.Binding
+		Down     key.Binding
+		NextTab  key.Binding
+		PageUp   key.Binding
+		PageDown key.Binding
+	}
+	// HistoryView lists the sent requests of a method to preview, diff and restore them.
+	HistoryView struct {
+		keyMap   HistoryKeyMap
+		commands *Commands
+		view     viewport.Model
+		title    TitleView
+		help     HelpView
+		entries  []store.HistoryEntry
+		current  store.HistoryEntry
+		selected int
+		tab      historyTab
+
+		width, height int
+	}
+	historyTab int
+)
+
+func DefaultHistoryKeyMap() HistoryKeyMap {
+	restore := key.NewBinding(key.WithKeys("enter"))
+	restore.SetHelp(`enter`, "restore")
+
+	up := key.NewBinding(key.WithKeys("up"))
+	up.SetHelp(`↑`, "up")
+
+	down := key.NewBinding(key.WithKeys("down"))
+	down.SetHelp(`↓`, "down")
+
+	nextTab := key.NewBinding(key.WithKeys("tab"))
+	nextTab.SetHelp(`tab`, "preview/diff")
+
+	pageUp := key.NewBinding(key.WithKeys("pgup"))
+	pageUp.SetHelp(`pgup`, "scroll up")
+
+	pageDown := key.NewBinding(key.WithKeys("pgdown"))
+	pageDown.SetHelp(`pgdown`, "scroll down")
+
+	return HistoryKeyMap{
+		Restore:  restore,
+		Up:       up,
+		Down:     down,
+		NextTab:  nextTab,
+		PageUp:   pageUp,
+		PageDown: pageDown,
+	}
+}
+
+func (h HistoryKeyMap) Bindings() []key.Binding {
+	return []key.Binding{h.Restore, h.Up, h.Down, h.NextTab, h.PageUp, h.PageDown}
+}
+
+func NewHistoryView(commands *Commands) *HistoryView {
+	keyMap := DefaultHistoryKeyMap()
+	return &HistoryView{
+		keyMap:   keyMap,
+		commands: commands,
+		view:     viewport.New(0, 0),
+		title:    NewTitleView("History"),
+		help:     NewHelpView(keyMap),
+	}
+}
+
+func (h *HistoryView) Init() tea.Cmd {
+	return nil
+}
+
+func (h *HistoryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, h.keyMap.Restore):
+			if h.selected < len(h.entries) {
+				entry := h.entries[h.selected]
+				return h, func() tea.Msg { return RestoreRequest{Entry: entry} }
+			}
+		case key.Matches(msg, h.keyMap.Up):
+			if h.selected > 0 {
+				h.selected--
+				h.refresh()
+			}
+		case key.Matches(msg, h.keyMap.Down):
+			if h.selected < len(h.entries)-1 {
+				h.selected++
+				h.refresh()
+			}
+		case key.Matches(msg, h.keyMap.NextTab):
+			h.tab = (h.tab + 1) % (diffTab + 1)
+			h.refresh()
+		case key.Matches(msg, h.keyMap.PageUp):
+			h.view.ViewUp()
+		case key.Matches(msg, h.keyMap.PageDown):
+			h.view.ViewDown()
+		}
+	case ShowHistory:
+		h.entries = msg.Entries
+		h.current = msg.Current
+		h.selected = 0
+		h.tab = previewTab
+		h.title.SetTitle("History: " + getShortMethodName(msg.Method))
+		h.refresh()
+	}
+	return h, nil
+}
+
+func (h *HistoryView) refresh() {
+	h.view.GotoTop()
+	if h.selected >= len(h.entries) {
+		h.view.SetContent(responseHeaderStyle.Render("No requests sent yet"))
+		return
+	}
+	entry := h.entries[h.selected]
+	switch h.tab {
+	case previewTab:
+		h.view.SetContent(h.renderPreview(entry))
+	case diffTab:
+		h.view.SetContent(renderDiff(requestText(entry), requestText(h.current)))
+	}
+}
+
+func (h *HistoryView) renderPreview(entry store.HistoryEntry) string {
+	lines := []string{fmt.Sprintf("%s in %s", h.renderStatus(entry.Status), formatElapsed(entry.Latency))}
+	lines = append(lines, requestText(entry))
+	if entry.Rendered != "" {
+		lines = append(lines, responseHeaderStyle.Render("Sent with the placeholders resolved:"), indentJSON(entry.Rendered))
+	}
+	for i, message := range entry.RenderedMessages {
+		if i == 0 {
+			lines = append(lines, responseHeaderStyle.Render("Sent with the placeholders resolved:"))
+		}
+		lines = append(lines, fmt.Sprintf("#%d\n%s", i+1, indentJSON(message)))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func (h *HistoryView) renderStatus(status string) string {
+	if status == "OK" {
+		return historyOKStyle.Render(status)
+	}
+	return historyErrorStyle.Render(status)
+}
+
+// requestText renders the request as the editor shows it: metadata, deadline and pretty JSON.
+func requestText(entry store.HistoryEntry) string {
+	parts := []string{}
+	if len(entry.Headers) > 0 {
+		parts = append(parts, strings.Join(entry.Headers, "\n"))
+	}
+	if entry.Deadline != "" {
+		parts = append(parts, "deadline: "+entry.Deadline)
+	}
+	if len(entry.Messages) > 0 {
+		for i, message := range entry.Messages {
+			parts = append(parts, fmt.Sprintf("#%d\n%s", i+1, indentJSON(message)))
+		}
+	} else {
+		parts = append(parts, indentJSON(entry.Payload))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func indentJSON(s string) string {
+	buf := bytes.Buffer{}
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// renderDiff shows the changes from the history entry to the editor content.
+func renderDiff(from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from + "\n"),
+		B:        difflib.SplitLines(to + "\n"),
+		FromFile: "history",
+		ToFile:   "editor",
+		Context:  3,
+	})
+	if err != nil {
+		return err.Error()
+	}
+	if diff == "" {
+		return responseHeaderStyle.Render("No changes, the editor has the same request")
+	}
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			lines[i] = responseHeaderStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffRemovedStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *HistoryView) listHeight() int {
+	height := len(h.entries)
+	if maxHeight := h.height / 3; height > maxHeight {
+		height = maxHeight
+	}
+	return height
+}
+
+func (h *HistoryView) renderEntry(entry store.HistoryEntry) string {
+	payload := compactJSON(entry.Payload)
+	if entry.Rendered != "" {
+		payload = compactJSON(entry.Rendered)
+	}
+	if len(entry.Messages) > 0 {
+		payload = fmt.Sprintf("%d messages", len(entry.Messages))
+	}
+	return fmt.Sprintf("%s  %s  %s  %s",
+		entry.Time.Local().Format(historyTimeFormat), h.renderStatus(entry.Status), formatElapsed(entry.Latency), payload)
+}
+
+func (h *HistoryView) listView() string {
+	height := h.listHeight()
+	// keep the selected entry visible
+	offset := 0
+	if h.selected >= height {
+		offset = h.selected - height + 1
+	}
+	lines := []string{}
+	for i := offset; i < len(h.entries) && i < offset+height; i++ {
+		if i == h.selected {
+			lines = append(lines, historySelectedStyle.MaxWidth(h.width).Render("│ "+h.renderEntry(h.entries[i])))
+		} else {
+			lines = append(lines, historyItemStyle.MaxWidth(h.width).Render(h.renderEntry(h.entries[i])))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *HistoryView) tabBarView() string {
+	tabs := []string{"Preview", "Diff with editor"}
+	rendered := make([]string, 0, len(tabs))
+	for i, tab := range tabs {
+		style := tabStyle
+		if historyTab(i) == h.tab {
+			style = activeTabStyle
+		}
+		rendered = append(rendered, style.Render(tab))
+	}
+	return tabBarStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+}
+
+func (h *HistoryView) View() string {
+	h.view.Width = h.width
+	h.view.Height = h.height - titleHeight - helpHeight - tabBarHeight - h.listHeight()
+	views := []string{h.title.View()}
+	if len(h.entries) > 0 {
+		views = append(views, h.listView())
+	}
+	views = append(views, h.tabBarView(), h.view.View(), h.help.View())
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
+}
+
+func (h *HistoryView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	h.width, h.height = msg.Width, msg.Height
+	h.help.SetWidth(msg.Width)
+}
@@ -1,18 +1,30 @@
 package tui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/profx5/jordi/internal/collection"
+	"github.com/profx5/jordi/internal/config"
+	"github.com/profx5/jordi/internal/grpc"
+	"github.com/profx5/jordi/internal/store"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+)
 
 type (
 	Back             struct{}
 	ShowServicesList struct {
-		Services []string
+		Services []grpc.ServiceInfo
+		// Unavailable is reported when the list replaces a method missing on the target
+		Unavailable error
 	}
 	ChosenService struct {
 		Service string
 	}
 	ShowMethodsList struct {
 		Service string
-		Methods []string
+		Methods []grpc.MethodInfo
 	}
 	ChosenMethod struct {
 		Method string
@@ -30,21 +42,121 @@ type (
 	}
 	ClearStatusMsg struct{}
 	ShowRequester  struct {
-		Method        string
-		InDescription string
-		InExample     string
+		Method          string
+		InDescription   string
+		InExample       string
+		Headers         []string
+		Deadline        string
+		ClientStreaming bool
+		// SavedAs is the collection and the name of a loaded saved request
+		SavedAs string
 	}
 	ShowResponseView struct {
-		ch <-chan tea.Msg
+		ch        <-chan tea.Msg
+		StartedAt time.Time
+		record    historyRecord
+	}
+	ReceivedHeaders struct {
+		ch      <-chan tea.Msg
+		Headers metadata.MD
 	}
 	ReceivedResponse struct {
-		ch       <-chan tea.Msg
-		Response string
+		ch         <-chan tea.Msg
+		Response   string
+		ReceivedAt time.Time
 	}
 	ReceivedStatus struct {
-		ch     <-chan tea.Msg
-		Status string
+		ch       <-chan tea.Msg
+		Status   string
+		Message  string
+		Details  []string
+		Trailers metadata.MD
 	}
 	ResendRequest struct {
 	}
+	StreamOpened struct {
+		ch        <-chan tea.Msg
+		Stream    *grpc.Stream
+		StartedAt time.Time
+		record    historyRecord
+	}
+	StreamMessagesSent struct {
+		Payloads []string
+		// Rendered are the payloads with the placeholders resolved
+		Rendered []string
+		Err      error
+	}
+	StreamHalfClosed struct{}
+	// CallStarted and CallFinished drive the elapsed time of the status bar
+	CallStarted struct {
+		StartedAt time.Time
+	}
+	CallFinished struct{}
+	elapsedTick  struct {
+		id int
+	}
+	// Connected and ConnectFailed report the environment the address was resolved with
+	Connected struct {
+		Target      config.Target
+		Environment string
+		Wrapper     *grpc.Wrapper
+	}
+	ConnectFailed struct {
+		Target      config.Target
+		Environment string
+		Error       error
+	}
+	// ConnectionState is reported for the wrapper, nil while it is being dialed
+	ConnectionState struct {
+		wrapper *grpc.Wrapper
+		State   connectivity.State
+	}
+	ShowTargetsList struct {
+		Configured []config.Target
+		Recent     []config.Target
+		Current    config.Target
+	}
+	ChosenTarget struct {
+		Target config.Target
+	}
+	ShowSearch       struct{}
+	AllMethodsLoaded struct {
+		Methods []grpc.MethodInfo
+		// Err reports the services whose methods failed to load
+		Err error
+	}
+	ShowTypes struct {
+		Symbol string
+	}
+	SymbolDescribed struct {
+		Info grpc.SymbolInfo
+	}
+	ShowHistory struct {
+		Method  string
+		Entries []store.HistoryEntry
+		// Current is the request in the editor, the diff compares to it
+		Current store.HistoryEntry
+	}
+	RestoreRequest struct {
+		Entry store.HistoryEntry
+	}
+	ShowCollections struct {
+		Collections []collection.Collection
+	}
+	ChosenRequest struct {
+		Collection string
+		Request    collection.Request
+	}
+	RequestSaved struct {
+		SavedAs string
+	}
+	ShowEnvironments struct {
+		Environments map[string]config.Environment
+		// Names are sorted, Current is empty when no environment is selected
+		Names   []string
+		Current string
+	}
+	ChosenEnvironment struct {
+		Name string
+	}
 )
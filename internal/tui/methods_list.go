@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/profx5/jordi/internal/grpc"
 )
 
 type (
@@ -15,6 +16,7 @@ type (
 	MethodsListItem struct {
 		Name      string
 		ShortName string
+		Info      grpc.MethodInfo
 	}
 	MethodsListView struct {
 		keyMap   MethodsListKeyMap
@@ -23,10 +25,11 @@ type (
 	}
 )
 
-func NewMethodsListItem(name string) MethodsListItem {
+func NewMethodsListItem(info grpc.MethodInfo) MethodsListItem {
 	return MethodsListItem{
-		Name:      name,
-		ShortName: getShortMethodName(name),
+		Name:      info.Name,
+		ShortName: getShortMethodName(info.Name),
+		Info:      info,
 	}
 }
 
@@ -39,14 +42,15 @@ func (i MethodsListItem) Title() string {
 }
 
 func (i MethodsListItem) Description() string {
-	return ""
+	description := fmt.Sprintf("%s → %s · %s", i.Info.InputType, i.Info.OutputType, i.Info.Kind())
+	if i.Info.Comment != "" {
+		description += " · " + oneLine(i.Info.Comment)
+	}
+	return description
 }
 
 func NewMethodsListView(commands *Commands) *MethodsListView {
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = false
-
-	view := list.New([]list.Item{}, delegate, 0, 0)
+	view := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	view.Title = "Methods"
 
 	return &MethodsListView{
@@ -65,7 +69,10 @@ func (m *MethodsListView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keyMap.Enter) {
-			return m, m.commands.LoadMethodMetadata(m.view.SelectedItem().(MethodsListItem).Name)
+			if item, ok := m.view.SelectedItem().(MethodsListItem); ok {
+				return m, m.commands.LoadMethodMetadata(item.Name)
+			}
+			return m, nil
 		}
 	case ShowMethodsList:
 		m.view.Title = fmt.Sprintf("Methods of %s", msg.Service)
@@ -1,16 +1,35 @@
 package tui
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/profx5/jordi/internal/collection"
+	"github.com/profx5/jordi/internal/grpc"
+	"github.com/profx5/jordi/internal/store"
+)
+
+const (
+	streamPaneHeaderHeight = 2
+	metadataSummaryHeight  = 1
+	metadataPaneAddHeight  = 2
+	metadataMinHeight      = 3
+	deadlineHeight         = 1
+	saveHeight             = 1
 )
 
 var (
-	descriptionStyle = lipgloss.NewStyle().PaddingLeft(2).Border(lipgloss.NormalBorder(), true, false)
+	descriptionStyle  = lipgloss.NewStyle().PaddingLeft(2).Border(lipgloss.NormalBorder(), true, false)
+	streamPaneStyle   = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false, false)
+	metadataPaneStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, true, false)
 )
 
 type (
@@ -18,27 +37,68 @@ type (
 		Send       key.Binding
 		Format     key.Binding
 		ToggleDesc key.Binding
+		Metadata   key.Binding
+		Deadline   key.Binding
+		Save       key.Binding
+		Preview    key.Binding
+		Queue      key.Binding
+		SendAll    key.Binding
+		CloseSend  key.Binding
 	}
 	RequestView struct {
-		keyMap      RequestKeyMap
-		commands    *Commands
-		inputView   textarea.Model
-		requestDesc string
-		title       TitleView
-		help        HelpView
+		keyMap       RequestKeyMap
+		commands     *Commands
+		inputView    textarea.Model
+		metadataView textarea.Model
+		deadlineView textinput.Model
+		saveView     textinput.Model
+		streamView   viewport.Model
+		requestDesc  string
+		title        TitleView
+		help         HelpView
 
 		method string
 		inDesc string
 
 		width, height int
 		showDesc      bool
+		showPreview   bool
+		preview       string
+		showMetadata  bool
+		editDeadline  bool
+		saving        bool
+		// savedAs is the collection and the name the request was loaded from or saved as
+		savedAs string
+
+		// streaming mode of client-streaming and bidi methods
+		streaming bool
+		stream    *grpc.Stream
+		streamCh  <-chan tea.Msg
+		opening   bool
+		// sending is set while messages are handed over to the stream, the ones sent
+		// meanwhile wait in pending to keep their order, closing half-closes it after them
+		sending   bool
+		closing   bool
+		pending   []string
+		queue     []string
+		streamLog []string
+		sent      int
+		received  int
+		record    historyRecord
 	}
 )
 
 func (r RequestKeyMap) Bindings() []key.Binding {
 	return []key.Binding{
 		r.Send,
+		r.SendAll,
+		r.Queue,
+		r.CloseSend,
 		r.Format,
+		r.Metadata,
+		r.Deadline,
+		r.Save,
+		r.Preview,
 		r.ToggleDesc,
 	}
 }
@@ -53,34 +113,90 @@ func DefaultRequestKeyMap() RequestKeyMap {
 	toggleDesc := key.NewBinding(key.WithKeys("tab"))
 	toggleDesc.SetHelp(`tab`, "description")
 
+	metadata := key.NewBinding(key.WithKeys("ctrl+o"))
+	metadata.SetHelp(`ctrl+o`, "metadata")
+
+	deadline := key.NewBinding(key.WithKeys("alt+t"))
+	deadline.SetHelp(`alt+t`, "deadline")
+
+	save := key.NewBinding(key.WithKeys("alt+s"))
+	save.SetHelp(`alt+s`, "save")
+
+	preview := key.NewBinding(key.WithKeys("alt+p"))
+	preview.SetHelp(`alt+p`, "preview")
+
+	queue := key.NewBinding(key.WithKeys("ctrl+q"), key.WithDisabled())
+	queue.SetHelp(`ctrl+q`, "queue")
+
+	sendAll := key.NewBinding(key.WithKeys("ctrl+g"), key.WithDisabled())
+	sendAll.SetHelp(`ctrl+g`, "send all")
+
+	closeSend := key.NewBinding(key.WithKeys("ctrl+x"), key.WithDisabled())
+	closeSend.SetHelp(`ctrl+x`, "close send")
+
 	return RequestKeyMap{
 		Send:       send,
 		Format:     format,
 		ToggleDesc: toggleDesc,
+		Metadata:   metadata,
+		Deadline:   deadline,
+		Save:       save,
+		Preview:    preview,
+		Queue:      queue,
+		SendAll:    sendAll,
+		CloseSend:  closeSend,
 	}
 }
 
+func (r *RequestKeyMap) SetStreaming(streaming bool) {
+	r.Queue.SetEnabled(streaming)
+	r.SendAll.SetEnabled(streaming)
+	r.CloseSend.SetEnabled(streaming)
+}
+
 func NewRequesterView(commands *Commands) *RequestView {
 	inputView := textarea.New()
 	inputView.ShowLineNumbers = false
 	inputView.CharLimit = 0
 	inputView.Prompt = ""
 
-	keyMap := DefaultRequestKeyMap()
+	metadataView := textarea.New()
+	metadataView.ShowLineNumbers = false
+	metadataView.CharLimit = 0
+	metadataView.Prompt = ""
+	metadataView.Placeholder = "authorization: Bearer token"
 
-	return &RequestView{
-		keyMap:      keyMap,
-		commands:    commands,
-		inputView:   inputView,
-		requestDesc: "",
-		title:       NewTitleView("Request"),
-		help:        NewHelpView(keyMap),
-		method:      "",
-		inDesc:      "",
-		showDesc:    false,
-		height:      0,
-		width:       0,
+	deadlineView := textinput.New()
+	deadlineView.Prompt = "Deadline: "
+	deadlineView.Placeholder = "none"
+	if commands.maxTime > 0 {
+		deadlineView.Placeholder = commands.maxTime.String()
 	}
+
+	saveView := textinput.New()
+	saveView.Prompt = "Save as: "
+	saveView.Placeholder = "collection/request name"
+
+	r := &RequestView{
+		keyMap:       DefaultRequestKeyMap(),
+		commands:     commands,
+		inputView:    inputView,
+		metadataView: metadataView,
+		deadlineView: deadlineView,
+		saveView:     saveView,
+		streamView:   viewport.New(0, 0),
+		requestDesc:  "",
+		title:        NewTitleView("Request"),
+		method:       "",
+		inDesc:       "",
+		showDesc:     false,
+		showMetadata: false,
+		height:       0,
+		width:        0,
+	}
+	// help reads the key map by pointer to hide disabled streaming bindings
+	r.help = NewHelpView(&r.keyMap)
+	return r
 }
 
 func (r *RequestView) Init() tea.Cmd {
@@ -100,64 +216,554 @@ func (r *RequestView) FormatInput() {
 	r.inputView.SetValue(string(b))
 }
 
+func (r *RequestView) ToggleMetadata() {
+	r.showMetadata = !r.showMetadata
+	r.editDeadline = false
+	r.deadlineView.Blur()
+	r.saving = false
+	r.saveView.Blur()
+	if r.showMetadata {
+		r.inputView.Blur()
+		r.metadataView.Focus()
+	} else {
+		r.metadataView.Blur()
+		r.inputView.Focus()
+	}
+}
+
+func (r *RequestView) ToggleDeadline() {
+	r.editDeadline = !r.editDeadline
+	r.showMetadata = false
+	r.metadataView.Blur()
+	r.saving = false
+	r.saveView.Blur()
+	if r.editDeadline {
+		r.inputView.Blur()
+		r.deadlineView.Focus()
+	} else {
+		r.deadlineView.Blur()
+		r.inputView.Focus()
+	}
+}
+
+func (r *RequestView) ToggleSave() {
+	r.saving = !r.saving
+	r.showMetadata = false
+	r.metadataView.Blur()
+	r.editDeadline = false
+	r.deadlineView.Blur()
+	if r.saving {
+		r.saveView.SetValue(r.savedAs)
+		r.saveView.CursorEnd()
+		r.inputView.Blur()
+		r.saveView.Focus()
+	} else {
+		r.saveView.Blur()
+		r.inputView.Focus()
+	}
+}
+
+// save keeps the edited request in the collection named in the save prompt.
+func (r *RequestView) save() tea.Cmd {
+	headers, deadline, err := r.callParams()
+	if err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	name, requestName := parseSavedName(r.saveView.Value())
+	return r.commands.SaveRequest(name, collection.Request{
+		Name:     requestName,
+		Method:   r.method,
+		Headers:  headers,
+		Deadline: deadline,
+		Payload:  collection.Payload(r.inputView.Value()),
+	})
+}
+
+// refreshPreview renders the request with the variables substituted, template functions are left as written.
+func (r *RequestView) refreshPreview() {
+	environment := r.commands.environment
+	if environment == "" {
+		environment = "none"
+	}
+	r.preview = r.previewText(environment)
+	// the editor keeps at least half of the screen
+	if lines := strings.Split(r.preview, "\n"); len(lines) > r.height/2 {
+		r.preview = strings.Join(lines[:r.height/2], "\n")
+	}
+}
+
+func (r *RequestView) previewText(environment string) string {
+	header := responseHeaderStyle.Render("Environment: " + environment)
+	address, err := r.commands.PreviewTarget()
+	if err != nil {
+		return header + "\n" + errorHeaderStyle.Render(err.Error())
+	}
+	header += "\n" + responseHeaderStyle.Render("Target: "+address)
+	if sources := r.commands.VariableSources(); len(sources) > 0 {
+		header += "\n" + responseHeaderStyle.Render("Variables: "+strings.Join(sources, " · "))
+	}
+	headers, deadline, err := r.callParams()
+	if err != nil {
+		return header + "\n" + errorHeaderStyle.Render(err.Error())
+	}
+	payload, headers, err := r.commands.Preview(r.inputView.Value(), headers)
+	if err != nil {
+		return header + "\n" + errorHeaderStyle.Render(err.Error())
+	}
+	return header + "\n\n" + requestText(store.HistoryEntry{Payload: payload, Headers: headers, Deadline: deadline})
+}
+
+func (r *RequestView) setTitle() {
+	title := getShortMethodName(r.method)
+	if r.savedAs != "" {
+		title += " · " + r.savedAs
+	}
+	r.title.SetTitle(title)
+}
+
+// callParams validates the metadata and the deadline of the request.
+func (r *RequestView) callParams() ([]string, string, error) {
+	headers, err := parseHeaders(r.metadataView.Value())
+	if err != nil {
+		return nil, "", err
+	}
+	deadline := strings.TrimSpace(r.deadlineView.Value())
+	if _, err := parseDeadline(deadline); err != nil {
+		return nil, "", err
+	}
+	return headers, deadline, nil
+}
+
+func (r *RequestView) send() tea.Cmd {
+	headers, deadline, err := r.callParams()
+	if err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	return r.commands.SendRequest(r.method, headers, r.inputView.Value(), deadline)
+}
+
+// current is the edited request, queued messages of a stream included.
+func (r *RequestView) current() store.HistoryEntry {
+	headers, err := parseHeaders(r.metadataView.Value())
+	if err != nil {
+		headers = strings.Split(strings.TrimSpace(r.metadataView.Value()), "\n")
+	}
+	return store.HistoryEntry{
+		Payload:  r.inputView.Value(),
+		Messages: r.queue,
+		Headers:  headers,
+		Deadline: strings.TrimSpace(r.deadlineView.Value()),
+	}
+}
+
+// restore puts the request of the history into the editor, messages of a stream are queued.
+func (r *RequestView) restore(entry store.HistoryEntry) {
+	payload := entry.Payload
+	if r.streaming && len(entry.Messages) > 0 {
+		r.queue = append([]string{}, entry.Messages...)
+		payload = entry.Messages[len(entry.Messages)-1]
+	}
+	r.inputView.SetValue(payload)
+	r.metadataView.SetValue(strings.Join(entry.Headers, "\n"))
+	r.deadlineView.SetValue(entry.Deadline)
+}
+
+func (r *RequestView) resetStream() {
+	r.stream = nil
+	r.streamCh = nil
+	r.opening = false
+	r.sending = false
+	r.closing = false
+	r.pending = nil
+	r.queue = nil
+	r.streamLog = nil
+	r.sent = 0
+	r.received = 0
+	r.streamView.SetContent("")
+}
+
+func (r *RequestView) showStreamPane() bool {
+	return r.streaming && (r.stream != nil || r.opening || len(r.streamLog) > 0 || len(r.queue) > 0)
+}
+
+func (r *RequestView) appendStreamLog(entry string) {
+	follow := r.streamView.AtBottom()
+	r.streamLog = append(r.streamLog, entry)
+	r.streamView.SetContent(strings.Join(r.streamLog, "\n"))
+	if follow {
+		r.streamView.GotoBottom()
+	}
+}
+
+func (r *RequestView) enqueue() tea.Cmd {
+	payload := r.inputView.Value()
+	if err := r.commands.CheckPayload(payload); err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	r.queue = append(r.queue, payload)
+	return nil
+}
+
+// nextPayloads takes the messages to send: the queued ones or the editor content.
+func (r *RequestView) nextPayloads(all bool) []string {
+	if len(r.queue) == 0 {
+		return []string{r.inputView.Value()}
+	}
+	if all {
+		payloads := r.queue
+		r.queue = nil
+		return payloads
+	}
+	payloads := r.queue[:1]
+	r.queue = r.queue[1:]
+	return payloads
+}
+
+func (r *RequestView) sendStream(all bool) tea.Cmd {
+	payloads := r.nextPayloads(all)
+	if r.stream != nil && r.sending {
+		r.pending = append(r.pending, payloads...)
+		return nil
+	}
+	if r.stream != nil {
+		return r.sendMessages(payloads)
+	}
+	headers, deadline, err := r.callParams()
+	if err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	r.pending = append(r.pending, payloads...)
+	if r.opening {
+		return nil
+	}
+	r.opening = true
+	return r.commands.OpenStream(r.method, headers, deadline)
+}
+
+// sendMessages hands the messages over to the open stream, one send at a time.
+func (r *RequestView) sendMessages(payloads []string) tea.Cmd {
+	r.sending = true
+	return r.commands.SendStreamMessages(r.stream, payloads)
+}
+
+// closeSend half-closes the stream once the messages being sent are handed over.
+func (r *RequestView) closeSend() tea.Cmd {
+	if r.stream == nil {
+		return nil
+	}
+	if r.sending {
+		r.closing = true
+		return nil
+	}
+	return r.commands.CloseStream(r.stream)
+}
+
+func compactJSON(s string) string {
+	buf := bytes.Buffer{}
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
 func (r *RequestView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if key.Matches(msg, r.keyMap.Send) {
-			return r, r.commands.SendRequest(r.method, r.inputView.Value())
+			if r.streaming {
+				return r, r.sendStream(false)
+			}
+			return r, r.send()
+		} else if key.Matches(msg, r.keyMap.SendAll) {
+			return r, r.sendStream(true)
+		} else if key.Matches(msg, r.keyMap.Queue) {
+			return r, r.enqueue()
+		} else if key.Matches(msg, r.keyMap.CloseSend) {
+			return r, r.closeSend()
+		} else if key.Matches(msg, r.keyMap.Metadata) {
+			r.ToggleMetadata()
+			return r, nil
+		} else if key.Matches(msg, r.keyMap.Deadline) || (r.editDeadline && msg.Type == tea.KeyEnter) {
+			r.ToggleDeadline()
+			return r, nil
+		} else if r.saving && msg.Type == tea.KeyEnter {
+			r.ToggleSave()
+			return r, r.save()
+		} else if key.Matches(msg, r.keyMap.Save) {
+			r.ToggleSave()
+			return r, nil
 		} else if key.Matches(msg, r.keyMap.Format) {
 			r.FormatInput()
 		} else if key.Matches(msg, r.keyMap.ToggleDesc) && r.inDesc != "" {
 			r.showDesc = !r.showDesc
+			r.showPreview = false
+		} else if key.Matches(msg, r.keyMap.Preview) {
+			r.showPreview = !r.showPreview
+			r.showDesc = false
+			r.refreshPreview()
+			return r, nil
 		} else {
 			cmds = append(cmds, r.commands.ClearStatusMsg())
 		}
 	case ShowRequester:
 		r.method = msg.Method
 		r.inDesc = msg.InDescription
+		r.streaming = msg.ClientStreaming
+		r.keyMap.SetStreaming(msg.ClientStreaming)
+		r.resetStream()
+
+		r.metadataView.Reset()
+		r.metadataView.SetValue(strings.Join(msg.Headers, "\n"))
+		r.metadataView.Blur()
+		r.showMetadata = false
+
+		r.deadlineView.Reset()
+		r.deadlineView.SetValue(msg.Deadline)
+		r.deadlineView.Blur()
+		r.editDeadline = false
+
+		r.saveView.Reset()
+		r.saveView.Blur()
+		r.saving = false
+		r.savedAs = msg.SavedAs
 
 		r.inputView.Reset()
 		r.inputView.SetValue(msg.InExample)
 		r.inputView.SetCursor(1)
 		r.inputView.Focus()
 
-		r.title.SetTitle(getShortMethodName(msg.Method))
+		r.setTitle()
 		cmds = append(cmds, r.commands.SetStatusOK())
 	case ResendRequest:
-		return r, r.commands.SendRequest(r.method, r.inputView.Value())
+		return r, r.send()
+	case RequestSaved:
+		r.savedAs = msg.SavedAs
+		r.setTitle()
+		cmds = append(cmds, r.commands.SetStatusMessage("Saved as "+msg.SavedAs, StatusMsgSuccess))
+	case RestoreRequest:
+		r.restore(msg.Entry)
+		cmds = append(cmds, r.commands.SetStatusMessage(
+			"Restored the request of "+msg.Entry.Time.Local().Format(historyTimeFormat), StatusMsgSuccess,
+		))
+	case StreamOpened:
+		pending := r.pending
+		r.pending = nil
+		r.opening = false
+		r.stream = msg.Stream
+		r.streamCh = msg.ch
+		r.record = msg.record
+		r.streamLog, r.sent, r.received = nil, 0, 0
+		r.streamView.SetContent("")
+		cmds = append(cmds, waitForMsg(msg.ch))
+		cmds = append(cmds, r.sendMessages(pending))
+		cmds = append(cmds, r.commands.SetStatus("Streaming", StatusTypeWarn))
+		cmds = append(cmds, r.commands.StartElapsed(msg.StartedAt))
+	case StreamMessagesSent:
+		r.record.entry.Messages = append(r.record.entry.Messages, msg.Payloads...)
+		r.record.entry.RenderedMessages = append(r.record.entry.RenderedMessages, msg.Rendered...)
+		for _, payload := range msg.Rendered {
+			r.sent++
+			r.appendStreamLog(fmt.Sprintf("→ #%d %s", r.sent, compactJSON(payload)))
+		}
+		r.sending = false
+		if msg.Err != nil {
+			r.pending = nil
+			r.closing = false
+			cmds = append(cmds, func() tea.Msg { return Err{Error: msg.Err} })
+		} else if len(r.pending) > 0 && r.stream != nil {
+			pending := r.pending
+			r.pending = nil
+			cmds = append(cmds, r.sendMessages(pending))
+		} else if r.closing {
+			r.closing = false
+			cmds = append(cmds, r.closeSend())
+		}
+	case StreamHalfClosed:
+		r.appendStreamLog("→ send side closed")
+	case ReceivedHeaders:
+		cmds = append(cmds, waitForMsg(msg.ch))
+		if msg.ch != r.streamCh || len(msg.Headers) == 0 {
+			break
+		}
+		r.appendStreamLog(responseHeaderStyle.Render("← headers") + "\n" + formatMetadata(msg.Headers))
+	case ReceivedResponse:
+		cmds = append(cmds, waitForMsg(msg.ch))
+		if msg.ch != r.streamCh {
+			break
+		}
+		r.received++
+		header := responseHeaderStyle.Render(fmt.Sprintf("← #%d %s", r.received, msg.ReceivedAt.Format(responseTimeFormat)))
+		r.appendStreamLog(header + "\n" + msg.Response)
+	case ReceivedStatus:
+		if msg.ch != r.streamCh {
+			break
+		}
+		r.appendStreamLog(fmt.Sprintf("■ %s", formatStatus(msg.Status, msg.Message)))
+		for _, detail := range msg.Details {
+			r.appendStreamLog(detail)
+		}
+		if len(msg.Trailers) > 0 {
+			r.appendStreamLog(responseHeaderStyle.Render("← trailers") + "\n" + formatMetadata(msg.Trailers))
+		}
+		r.stream = nil
+		r.streamCh = nil
+		cmds = append(cmds, r.commands.AddHistory(r.record, msg.Status))
+		statusMsgType := StatusMsgError
+		if msg.Status == "OK" {
+			statusMsgType = StatusMsgSuccess
+		}
+		cmds = append(cmds, r.commands.SetStatusMessage(formatStatus(msg.Status, msg.Message), statusMsgType))
+		cmds = append(cmds, r.commands.SetStatusOK())
+		cmds = append(cmds, r.commands.StopElapsed())
+	case Back:
+		if r.stream != nil || r.opening {
+			cmds = append(cmds, r.commands.CancelInvoke())
+			cmds = append(cmds, r.commands.SetStatusOK())
+			cmds = append(cmds, r.commands.StopElapsed())
+		}
+		r.resetStream()
 	}
 
 	updInput, cmd := r.inputView.Update(msg)
 	r.inputView = updInput
 	cmds = append(cmds, cmd)
 
+	updMetadata, cmd := r.metadataView.Update(msg)
+	r.metadataView = updMetadata
+	cmds = append(cmds, cmd)
+
+	updDeadline, cmd := r.deadlineView.Update(msg)
+	r.deadlineView = updDeadline
+	cmds = append(cmds, cmd)
+
+	updSave, cmd := r.saveView.Update(msg)
+	r.saveView = updSave
+	cmds = append(cmds, cmd)
+
+	switch msg.(type) {
+	case tea.KeyMsg, ShowRequester, RestoreRequest, ChosenEnvironment:
+		if r.showPreview {
+			r.refreshPreview()
+		}
+	}
+
 	return r, tea.Batch(cmds...)
 }
 
+func (r *RequestView) streamPaneView() string {
+	state := "closed"
+	if r.stream != nil || r.opening {
+		state = "open"
+	}
+	header := responseHeaderStyle.Render(fmt.Sprintf(
+		"Stream %s · sent %d · received %d · queued %d", state, r.sent, r.received, len(r.queue),
+	))
+	return streamPaneStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, r.streamView.View()))
+}
+
+func (r *RequestView) metadataSummary() string {
+	headers, err := parseHeaders(r.metadataView.Value())
+	if err != nil {
+		return responseHeaderStyle.Render("Metadata: " + err.Error())
+	}
+	names := []string{}
+	for _, header := range headers {
+		names = append(names, headerName(header))
+	}
+	return responseHeaderStyle.Render("Metadata: " + strings.Join(names, ", "))
+}
+
+func (r *RequestView) metadataHeight() int {
+	if !r.showMetadata {
+		if strings.TrimSpace(r.metadataView.Value()) == "" {
+			return 0
+		}
+		return metadataSummaryHeight
+	}
+	height := r.metadataView.LineCount() + 1
+	if height < metadataMinHeight {
+		height = metadataMinHeight
+	}
+	if maxHeight := r.height / 3; height > maxHeight {
+		height = maxHeight
+	}
+	return height
+}
+
+func (r *RequestView) showDeadline() bool {
+	return r.editDeadline || strings.TrimSpace(r.deadlineView.Value()) != ""
+}
+
 func (r *RequestView) View() string {
 	r.SyncSize()
 
-	views := []string{r.title.View(), r.inputView.View()}
-	if r.showDesc {
-		views = append(views, descriptionStyle.Render(r.inDesc))
+	views := []string{r.title.View()}
+	if r.showMetadata {
+		views = append(views, metadataPaneStyle.Render(lipgloss.JoinVertical(
+			lipgloss.Left, responseHeaderStyle.Render("Metadata"), r.metadataView.View(),
+		)))
+	} else if r.metadataHeight() > 0 {
+		views = append(views, r.metadataSummary())
+	}
+	if r.showDeadline() {
+		views = append(views, r.deadlineView.View())
+	}
+	if r.saving {
+		views = append(views, r.saveView.View())
+	}
+	views = append(views, r.inputView.View())
+	if r.showStreamPane() {
+		views = append(views, r.streamPaneView())
+	}
+	if pane, ok := r.pane(); ok {
+		views = append(views, descriptionStyle.Render(pane))
 	}
 	views = append(views, r.help.View())
 
 	return lipgloss.JoinVertical(lipgloss.Left, views...)
 }
 
+// pane is the content shown below the editor: the description or the preview.
+func (r *RequestView) pane() (string, bool) {
+	if r.showPreview {
+		return r.preview, true
+	}
+	return r.inDesc, r.showDesc
+}
+
 func (r *RequestView) HandleWindowSize(msg tea.WindowSizeMsg) {
 	r.width, r.height = msg.Width, msg.Height
 }
 
 func (r *RequestView) SyncSize() {
 	r.inputView.SetWidth(r.width)
+	r.metadataView.SetWidth(r.width)
+	r.deadlineView.Width = r.width - len(r.deadlineView.Prompt) - 1
+	r.saveView.Width = r.width - len(r.saveView.Prompt) - 1
+	r.streamView.Width = r.width
 	r.help.SetWidth(r.width)
 
 	height := r.height - helpHeight - titleHeight
-	if r.showDesc {
-		height = height - helpHeight - countLines(r.inDesc) - 2
+	if metadataHeight := r.metadataHeight(); r.showMetadata {
+		r.metadataView.SetHeight(metadataHeight)
+		height -= metadataHeight + metadataPaneAddHeight
+	} else {
+		height -= metadataHeight
+	}
+	if r.showDeadline() {
+		height -= deadlineHeight
+	}
+	if r.saving {
+		height -= saveHeight
+	}
+	if pane, ok := r.pane(); ok {
+		height = height - helpHeight - countLines(pane) - 2
+	}
+	if r.showStreamPane() {
+		paneHeight := height / 2
+		r.streamView.Height = paneHeight - streamPaneHeaderHeight
+		height -= paneHeight
 	}
 	r.inputView.SetHeight(height)
 }
@@ -1,22 +1,80 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/profx5/jordi/internal/vars"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	responseTimeFormat = "15:04:05.000"
+	tabBarHeight       = 1
+	capturesHeight     = 1
+)
+
+const (
+	bodyTab responseTab = iota
+	headersTab
+	trailersTab
+)
+
+var (
+	responseHeaderStyle = lipgloss.NewStyle().Faint(true)
+	tabBarStyle         = lipgloss.NewStyle().PaddingLeft(2)
+	tabStyle            = lipgloss.NewStyle().Padding(0, 1).Faint(true)
+	activeTabStyle      = lipgloss.NewStyle().Padding(0, 1).Bold(true).Underline(true)
+	errorHeaderStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ff0000"))
 )
 
 type (
 	ResponseView struct {
-		keyMap   ResponseKeyMap
-		commands *Commands
-		view     viewport.Model
-		title    TitleView
-		help     HelpView
+		keyMap    ResponseKeyMap
+		commands  *Commands
+		view      viewport.Model
+		title     TitleView
+		help      HelpView
+		ch        <-chan tea.Msg
+		responses []responseItem
+		headers   metadata.MD
+		trailers  metadata.MD
+		callErr   *callError
+		tab       responseTab
+		// startedAt of the call in flight, zero once it is finished
+		startedAt time.Time
+		// cancelledAfter is the elapsed time when the user cancelled the call
+		cancelledAfter time.Duration
+		record         historyRecord
+		// rules capture fields of the responses of the method into variables
+		rules       []vars.Capture
+		captureView textinput.Model
+		capturing   bool
+
+		width, height int
+	}
+	callError struct {
+		code    string
+		message string
+		details []string
 	}
 	ResponseKeyMap struct {
-		resend key.Binding
+		resend  key.Binding
+		cancel  key.Binding
+		nextTab key.Binding
+		capture key.Binding
+		clear   key.Binding
+	}
+	responseTab  int
+	responseItem struct {
+		receivedAt time.Time
+		body       string
 	}
 )
 
@@ -24,64 +82,260 @@ func DefaultResponseKeyMap() ResponseKeyMap {
 	resend := key.NewBinding(key.WithKeys("ctrl+r"))
 	resend.SetHelp(`ctrl+r`, "resend")
 
+	cancel := key.NewBinding(key.WithKeys("ctrl+x"), key.WithDisabled())
+	cancel.SetHelp(`ctrl+x`, "cancel")
+
+	nextTab := key.NewBinding(key.WithKeys("tab"))
+	nextTab.SetHelp(`tab`, "body/headers/trailers")
+
+	capture := key.NewBinding(key.WithKeys("ctrl+k"))
+	capture.SetHelp(`ctrl+k`, "capture")
+
+	clear := key.NewBinding(key.WithKeys("alt+k"))
+	clear.SetHelp(`alt+k`, "clear captured")
+
 	return ResponseKeyMap{
-		resend: resend,
+		resend:  resend,
+		cancel:  cancel,
+		nextTab: nextTab,
+		capture: capture,
+		clear:   clear,
 	}
 }
 
 func (r ResponseKeyMap) Bindings() []key.Binding {
-	return []key.Binding{r.resend}
+	return []key.Binding{r.resend, r.cancel, r.nextTab, r.capture, r.clear}
 }
 
 func NewResponseView(commands *Commands) *ResponseView {
 	view := viewport.New(0, 0)
 
-	keyMap := DefaultResponseKeyMap()
-	return &ResponseView{
-		keyMap:   keyMap,
-		commands: commands,
-		view:     view,
-		title:    NewTitleView("Response"),
-		help:     NewHelpView(keyMap),
+	captureView := textinput.New()
+	captureView.Prompt = "Capture: "
+	captureView.Placeholder = "name = $.path.to[0].field"
+
+	r := &ResponseView{
+		keyMap:      DefaultResponseKeyMap(),
+		commands:    commands,
+		view:        view,
+		title:       NewTitleView("Response"),
+		captureView: captureView,
 	}
+	// help reads the key map by pointer to show cancel only while the call is in flight
+	r.help = NewHelpView(&r.keyMap)
+	return r
 }
 
 func (r *ResponseView) Init() tea.Cmd {
 	return nil
 }
 
-func (r *ResponseView) waitForMsg(sub <-chan tea.Msg) tea.Cmd {
-	return func() tea.Msg {
-		return <-sub
+func (r *ResponseView) renderResponses() string {
+	parts := make([]string, 0, len(r.responses))
+	for i, resp := range r.responses {
+		header := fmt.Sprintf("#%d  %s", i+1, resp.receivedAt.Format(responseTimeFormat))
+		parts = append(parts, responseHeaderStyle.Render(header)+"\n"+resp.body)
+	}
+	if r.cancelledAfter > 0 {
+		parts = append(parts, errorHeaderStyle.Render("Cancelled after "+formatElapsed(r.cancelledAfter)))
+	} else if r.callErr != nil {
+		parts = append(parts, r.callErr.View())
 	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (e *callError) View() string {
+	lines := []string{errorHeaderStyle.Render("Error: " + e.code)}
+	if e.message != "" {
+		lines = append(lines, "Message: "+e.message)
+	}
+	if len(e.details) > 0 {
+		lines = append(lines, "Details:")
+		lines = append(lines, e.details...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *ResponseView) renderMetadata(md metadata.MD) string {
+	if len(md) == 0 {
+		return responseHeaderStyle.Render("(empty)")
+	}
+	return formatMetadata(md)
+}
+
+// refresh renders the current tab, following the bottom on new content.
+func (r *ResponseView) refresh(follow bool) {
+	switch r.tab {
+	case bodyTab:
+		r.view.SetContent(r.renderResponses())
+	case headersTab:
+		r.view.SetContent(r.renderMetadata(r.headers))
+	case trailersTab:
+		r.view.SetContent(r.renderMetadata(r.trailers))
+	}
+	if follow {
+		r.view.GotoBottom()
+	}
+}
+
+func (r *ResponseView) reset() {
+	r.responses = nil
+	r.headers = nil
+	r.trailers = nil
+	r.callErr = nil
+	r.setInFlight(time.Time{})
+	r.cancelledAfter = 0
+	r.tab = bodyTab
+	r.view.SetContent("")
+	r.view.GotoTop()
+}
+
+func (r *ResponseView) toggleCapture() tea.Cmd {
+	r.capturing = !r.capturing
+	r.syncSize()
+	if !r.capturing {
+		r.captureView.Blur()
+		return nil
+	}
+	r.captureView.Reset()
+	r.captureView.Focus()
+	return textinput.Blink
+}
+
+// addCapture keeps the rule of the capture prompt and applies it to the last response.
+func (r *ResponseView) addCapture() tea.Cmd {
+	method := r.record.method
+	if err := r.commands.SetCaptureRule(method, r.captureView.Value()); err != nil {
+		return func() tea.Msg { return Err{Error: err} }
+	}
+	r.rules = r.commands.CaptureRules(method)
+	r.syncSize()
+	if len(r.responses) == 0 || r.callErr != nil || !r.startedAt.IsZero() {
+		return nil
+	}
+	names, err := r.commands.Capture(method, r.responses[len(r.responses)-1].body)
+	if err != nil {
+		return r.commands.SetStatusMessage(err.Error(), StatusMsgError)
+	}
+	return r.commands.SetStatusMessage("Captured "+strings.Join(names, ", "), StatusMsgSuccess)
+}
+
+// setInFlight tracks the start of the running call, zero time finishes it.
+func (r *ResponseView) setInFlight(startedAt time.Time) {
+	r.startedAt = startedAt
+	r.keyMap.cancel.SetEnabled(!startedAt.IsZero())
 }
 
 func (r *ResponseView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if r.capturing && msg.Type == tea.KeyEnter {
+			r.toggleCapture()
+			return r, r.addCapture()
+		} else if key.Matches(msg, r.keyMap.capture) {
+			return r, r.toggleCapture()
+		} else if key.Matches(msg, r.keyMap.clear) && !r.capturing {
+			r.commands.ClearCaptured()
+			return r, r.commands.SetStatusMessage("Captured values cleared", StatusMsgSuccess)
+		} else if r.capturing {
+			var cmd tea.Cmd
+			r.captureView, cmd = r.captureView.Update(msg)
+			return r, cmd
+		}
 		if key.Matches(msg, r.keyMap.resend) {
 			cmds = append(cmds, r.commands.ResendRequest())
+		} else if key.Matches(msg, r.keyMap.cancel) {
+			// the partial output stays, the call reports its status when it is aborted
+			r.cancelledAfter = time.Since(r.startedAt)
+			r.keyMap.cancel.SetEnabled(false)
+			cmds = append(cmds, r.commands.CancelInvoke())
+		} else if key.Matches(msg, r.keyMap.nextTab) {
+			r.tab = (r.tab + 1) % (trailersTab + 1)
+			r.view.GotoTop()
+			r.refresh(false)
 		}
 	case ShowResponseView:
-		cmds = append(cmds, r.waitForMsg(msg.ch))
+		r.ch = msg.ch
+		r.reset()
+		r.setInFlight(msg.StartedAt)
+		r.record = msg.record
+		r.rules = r.commands.CaptureRules(msg.record.method)
+		r.syncSize()
+		cmds = append(cmds, waitForMsg(msg.ch))
 		cmds = append(cmds, r.commands.SetStatusLoading())
+		cmds = append(cmds, r.commands.StartElapsed(msg.StartedAt))
+	case ReceivedHeaders:
+		cmds = append(cmds, waitForMsg(msg.ch))
+		if msg.ch != r.ch {
+			break
+		}
+		r.headers = msg.Headers
+		r.refresh(false)
 	case ReceivedResponse:
-		r.view.SetContent(msg.Response)
-		cmds = append(cmds, r.waitForMsg(msg.ch))
+		cmds = append(cmds, waitForMsg(msg.ch))
+		// drain messages of a stream that was replaced by a newer request
+		if msg.ch != r.ch {
+			break
+		}
+		// keep following the stream unless the user scrolled up
+		follow := r.tab == bodyTab && len(r.responses) > 0 && r.view.AtBottom()
+		r.responses = append(r.responses, responseItem{receivedAt: msg.ReceivedAt, body: msg.Response})
+		r.refresh(follow)
+		cmds = append(cmds, r.commands.SetStatus(fmt.Sprintf("Received %d", len(r.responses)), StatusTypeWarn))
 	case ReceivedStatus:
+		if msg.ch != r.ch {
+			break
+		}
+		r.trailers = msg.Trailers
 		statusMsgType := StatusMsgError
 		if msg.Status == "OK" {
 			statusMsgType = StatusMsgSuccess
+			// the call finished before the cancellation got through
+			r.cancelledAfter = 0
+		} else {
+			r.callErr = &callError{code: msg.Status, message: msg.Message, details: msg.Details}
+		}
+		follow := r.tab == bodyTab && r.view.AtBottom()
+		r.refresh(follow)
+		status := fmt.Sprintf("%s in %s", formatStatus(msg.Status, msg.Message), formatElapsed(time.Since(r.startedAt)))
+		if r.cancelledAfter > 0 {
+			status = "Cancelled after " + formatElapsed(r.cancelledAfter)
+		}
+		if len(r.responses) > 1 {
+			status = fmt.Sprintf("%s, %d messages", status, len(r.responses))
+		}
+		if msg.Status == "OK" && len(r.rules) > 0 && len(r.responses) > 0 {
+			names, err := r.commands.Capture(r.record.method, r.responses[len(r.responses)-1].body)
+			if len(names) > 0 {
+				status = fmt.Sprintf("%s, captured %s", status, strings.Join(names, ", "))
+			}
+			if err != nil {
+				status = fmt.Sprintf("%s, %s", status, err)
+				statusMsgType = StatusMsgError
+			}
 		}
-		cmds = append(cmds, func() tea.Msg {
-			return NewStatusMessage{Msg: msg.Status, Type: statusMsgType}
-		})
+		r.setInFlight(time.Time{})
+		cmds = append(cmds, r.commands.AddHistory(r.record, msg.Status))
+		cmds = append(cmds, r.commands.SetStatusMessage(status, statusMsgType))
 		cmds = append(cmds, r.commands.SetStatusOK())
+		cmds = append(cmds, r.commands.StopElapsed())
+	case Err:
+		// the call failed before it got a status
+		if !r.startedAt.IsZero() {
+			r.setInFlight(time.Time{})
+			cmds = append(cmds, r.commands.SetStatusOK())
+			cmds = append(cmds, r.commands.StopElapsed())
+		}
 	case Back:
-		r.view.SetContent("")
+		r.ch = nil
+		r.reset()
+		r.capturing = false
+		r.captureView.Blur()
+		cmds = append(cmds, r.commands.CancelInvoke())
 		cmds = append(cmds, r.commands.ClearStatusMsg())
 		cmds = append(cmds, r.commands.SetStatusOK())
+		cmds = append(cmds, r.commands.StopElapsed())
 	}
 	var cmd tea.Cmd
 	r.view, cmd = r.view.Update(msg)
@@ -89,12 +343,57 @@ func (r *ResponseView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return r, tea.Batch(cmds...)
 }
 
+func (r *ResponseView) tabBarView() string {
+	tabs := []string{
+		"Body",
+		fmt.Sprintf("Headers (%d)", len(r.headers)),
+		fmt.Sprintf("Trailers (%d)", len(r.trailers)),
+	}
+	rendered := make([]string, 0, len(tabs))
+	for i, tab := range tabs {
+		style := tabStyle
+		if responseTab(i) == r.tab {
+			style = activeTabStyle
+		}
+		rendered = append(rendered, style.Render(tab))
+	}
+	return tabBarStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+}
+
+func (r *ResponseView) capturesView() string {
+	rules := make([]string, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule.String())
+	}
+	return tabBarStyle.Copy().MaxWidth(r.width).Render(responseHeaderStyle.Render("Captures: " + strings.Join(rules, " · ")))
+}
+
 func (r *ResponseView) View() string {
-	return lipgloss.JoinVertical(lipgloss.Left, r.title.View(), r.view.View(), r.help.View())
+	views := []string{r.title.View(), r.tabBarView()}
+	if len(r.rules) > 0 {
+		views = append(views, r.capturesView())
+	}
+	if r.capturing {
+		views = append(views, tabBarStyle.Render(r.captureView.View()))
+	}
+	views = append(views, r.view.View(), r.help.View())
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
+}
+
+func (r *ResponseView) syncSize() {
+	r.view.Width = r.width
+	r.view.Height = r.height - helpHeight - titleHeight - tabBarHeight
+	if len(r.rules) > 0 {
+		r.view.Height -= capturesHeight
+	}
+	if r.capturing {
+		r.view.Height -= capturesHeight
+	}
+	r.captureView.Width = r.width - len(r.captureView.Prompt) - 4
+	r.help.SetWidth(r.width)
 }
 
 func (r *ResponseView) HandleWindowSize(msg tea.WindowSizeMsg) {
-	r.view.Width = msg.Width
-	r.view.Height = msg.Height - helpHeight - titleHeight
-	r.help.SetWidth(msg.Width)
+	r.width, r.height = msg.Width, msg.Height
+	r.syncSize()
 }
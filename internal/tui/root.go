@@ -1,12 +1,14 @@
 package tui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/profx5/jordi/internal/config"
-	"github.com/profx5/jordi/internal/grpc"
 	"github.com/profx5/jordi/internal/store"
+	"google.golang.org/grpc/connectivity"
 )
 
 type View int
@@ -16,6 +18,12 @@ const (
 	Methods  View = iota
 	Request  View = iota
 	Response View = iota
+	Targets  View = iota
+	Search   View = iota
+	Types    View = iota
+	History  View = iota
+	Saved    View = iota
+	Envs     View = iota
 
 	statusBarHeight = 1
 )
@@ -24,9 +32,24 @@ type (
 	RootKeyMap struct {
 		Back      key.Binding
 		ForceQuit key.Binding
+		Reconnect key.Binding
+		Targets   key.Binding
+		Search    key.Binding
+		Types     key.Binding
+		History   key.Binding
+		Saved     key.Binding
+		Envs      key.Binding
 	}
 	Root struct {
-		initMethod       string
+		initMethod string
+		// connecting is set while the connection is dialed
+		connecting bool
+		// loaded is set once the initial screen got its content
+		loaded bool
+		// reloadMethod is opened again once connected to a new target
+		reloadMethod     string
+		targets          []config.Target
+		previousView     View
 		keyMap           RootKeyMap
 		commands         *Commands
 		currentView      View
@@ -34,17 +57,34 @@ type (
 		methodsListView  *MethodsListView
 		requestView      *RequestView
 		responseView     *ResponseView
+		targetsListView  *TargetsListView
+		searchView       *SearchView
+		typesView        *TypesView
+		historyView      *HistoryView
+		collectionsView  *CollectionsView
+		envsListView     *EnvironmentsListView
 		statusView       *StatusView
 	}
 )
 
-func NewRoot(config config.Config, grpc *grpc.Wrapper, store *store.Store) *Root {
-	commands := NewCommands(grpc, store)
+func NewRoot(config config.Config, dial Dialer, store, global *store.Store) *Root {
+	commands := NewCommands(config, dial, store, global)
+	statusView := NewStatusView()
+	statusView.SetTarget(commands.target.Title())
+	statusView.SetEnvironment(config.Environment)
 	return &Root{
 		initMethod: config.Method,
+		targets:    config.Targets,
 		keyMap: RootKeyMap{
 			Back:      key.NewBinding(key.WithKeys("esc")),
 			ForceQuit: key.NewBinding(key.WithKeys("ctrl+c")),
+			Reconnect: key.NewBinding(key.WithKeys("f5")),
+			Targets:   key.NewBinding(key.WithKeys("f2")),
+			Search:    key.NewBinding(key.WithKeys("ctrl+p")),
+			Types:     key.NewBinding(key.WithKeys("ctrl+y")),
+			History:   key.NewBinding(key.WithKeys("f3")),
+			Saved:     key.NewBinding(key.WithKeys("f4")),
+			Envs:      key.NewBinding(key.WithKeys("f6")),
 		},
 		commands:         commands,
 		currentView:      Services,
@@ -52,19 +92,47 @@ func NewRoot(config config.Config, grpc *grpc.Wrapper, store *store.Store) *Root
 		methodsListView:  NewMethodsListView(commands),
 		requestView:      NewRequesterView(commands),
 		responseView:     NewResponseView(commands),
-		statusView:       NewStatusView(),
+		targetsListView:  NewTargetsListView(commands),
+		searchView:       NewSearchView(commands),
+		typesView:        NewTypesView(commands),
+		historyView:      NewHistoryView(commands),
+		collectionsView:  NewCollectionsView(commands),
+		envsListView:     NewEnvironmentsListView(commands),
+		statusView:       statusView,
 	}
 }
 
 func (m *Root) Init() tea.Cmd {
-	cmds := []tea.Cmd{}
 	if m.initMethod != "" {
 		m.currentView = Request
-		cmds = append(cmds, m.commands.LoadMethodMetadata(m.initMethod))
-	} else {
-		cmds = append(cmds, m.commands.LoadServices())
 	}
-	return tea.Batch(cmds...)
+	m.connecting = true
+	return m.commands.Connect()
+}
+
+// Close closes the connection when the program exits.
+func (m *Root) Close() {
+	m.commands.Close()
+}
+
+func (m *Root) load() tea.Cmd {
+	if !m.loaded {
+		m.loaded = true
+		if m.initMethod != "" {
+			return m.commands.LoadMethodMetadata(m.initMethod)
+		}
+		return m.commands.LoadServices()
+	}
+	if m.reloadMethod != "" {
+		method := m.reloadMethod
+		m.reloadMethod = ""
+		return m.commands.ReloadMethod(method)
+	}
+	// the services list is empty when the first connection failed
+	if m.currentView == Services {
+		return m.commands.LoadServices()
+	}
+	return nil
 }
 
 func (m *Root) CurrentView() tea.Model {
@@ -77,12 +145,37 @@ func (m *Root) CurrentView() tea.Model {
 		return m.requestView
 	case Response:
 		return m.responseView
+	case Targets:
+		return m.targetsListView
+	case Search:
+		return m.searchView
+	case Types:
+		return m.typesView
+	case History:
+		return m.historyView
+	case Saved:
+		return m.collectionsView
+	case Envs:
+		return m.envsListView
 	}
 	panic("Unknown view")
 }
 
 func (m *Root) UpdateCurrentView(msg tea.Msg) tea.Cmd {
+	return m.updateView(m.currentView, msg)
+}
+
+// isOverlay tells whether the view is shown on top of the previous one.
+func (m *Root) isOverlay() bool {
 	switch m.currentView {
+	case Targets, Search, Types, History, Saved, Envs:
+		return true
+	}
+	return false
+}
+
+func (m *Root) updateView(view View, msg tea.Msg) tea.Cmd {
+	switch view {
 	case Services:
 		updModel, cmd := m.servicesListView.Update(msg)
 		m.servicesListView = updModel.(*ServicesListView)
@@ -99,15 +192,165 @@ func (m *Root) UpdateCurrentView(msg tea.Msg) tea.Cmd {
 		updModel, cmd := m.responseView.Update(msg)
 		m.responseView = updModel.(*ResponseView)
 		return cmd
+	case Targets:
+		updModel, cmd := m.targetsListView.Update(msg)
+		m.targetsListView = updModel.(*TargetsListView)
+		return cmd
+	case Search:
+		updModel, cmd := m.searchView.Update(msg)
+		m.searchView = updModel.(*SearchView)
+		return cmd
+	case Types:
+		updModel, cmd := m.typesView.Update(msg)
+		m.typesView = updModel.(*TypesView)
+		return cmd
+	case History:
+		updModel, cmd := m.historyView.Update(msg)
+		m.historyView = updModel.(*HistoryView)
+		return cmd
+	case Saved:
+		updModel, cmd := m.collectionsView.Update(msg)
+		m.collectionsView = updModel.(*CollectionsView)
+		return cmd
+	case Envs:
+		updModel, cmd := m.envsListView.Update(msg)
+		m.envsListView = updModel.(*EnvironmentsListView)
+		return cmd
 	}
 	return nil
 }
 
+// openView shows the view on top of the current one, Back returns to it.
+func (m *Root) openView(view View) {
+	if !m.isOverlay() {
+		m.previousView = m.currentView
+	}
+	m.currentView = view
+}
+
+func (m *Root) showTypes() tea.Cmd {
+	m.openView(Types)
+	return m.UpdateCurrentView(ShowTypes{Symbol: m.requestView.method})
+}
+
+func (m *Root) showHistory() tea.Cmd {
+	method := m.requestView.method
+	entries, err := m.commands.History(method)
+	if err != nil {
+		return m.commands.SetStatusMessage(err.Error(), StatusMsgError)
+	}
+	m.openView(History)
+	return m.UpdateCurrentView(ShowHistory{Method: method, Entries: entries, Current: m.requestView.current()})
+}
+
+// restoreRequest leaves the history for the request editor, a call in flight is cancelled.
+func (m *Root) restoreRequest(msg RestoreRequest) tea.Cmd {
+	cmds := []tea.Cmd{}
+	if m.previousView == Response {
+		cmds = append(cmds, m.updateView(Response, Back{}))
+	}
+	m.currentView = Request
+	cmds = append(cmds, m.UpdateCurrentView(msg))
+	return tea.Batch(cmds...)
+}
+
+func (m *Root) showSearch() tea.Cmd {
+	m.openView(Search)
+	return tea.Batch(m.UpdateCurrentView(ShowSearch{}), m.commands.LoadAllMethods())
+}
+
+// showEnvironments lists the configured environments to choose from.
+func (m *Root) showEnvironments() tea.Cmd {
+	m.openView(Envs)
+	return m.UpdateCurrentView(ShowEnvironments{
+		Environments: m.commands.environments,
+		Names:        m.commands.EnvironmentNames(),
+		Current:      m.commands.environment,
+	})
+}
+
+// chooseEnvironment selects the environment, the target is reconnected when its address depends on it.
+func (m *Root) chooseEnvironment(name string) tea.Cmd {
+	cmds := []tea.Cmd{}
+	reconnect := m.commands.SetEnvironment(name)
+	m.currentView = m.previousView
+	m.statusView.SetEnvironment(name)
+	cmds = append(cmds, m.updateView(Request, ChosenEnvironment{Name: name}))
+	if name == "" {
+		cmds = append(cmds, m.commands.SetStatusMessage("No environment", StatusMsgSuccess))
+	} else {
+		cmds = append(cmds, m.commands.SetStatusMessage("Environment "+name, StatusMsgSuccess))
+	}
+	// a connection being dialed resolved the address with the previous environment
+	if reconnect {
+		m.connecting = true
+		m.commands.SetWrapper(nil)
+		cmds = append(cmds, m.commands.Connect())
+	}
+	return tea.Batch(cmds...)
+}
+
+// showCollections lists the saved requests of all collections.
+func (m *Root) showCollections() tea.Cmd {
+	m.openView(Saved)
+	return m.commands.LoadCollections()
+}
+
+// chooseMethod opens the method found by the search.
+func (m *Root) chooseMethod(method string) tea.Cmd {
+	return tea.Batch(m.clearMethodsList(), m.commands.LoadMethodMetadata(method))
+}
+
+func (m *Root) chooseRequest(msg ChosenRequest) tea.Cmd {
+	return tea.Batch(m.clearMethodsList(), m.commands.OpenSavedRequest(msg.Collection, msg.Request))
+}
+
+// clearMethodsList empties the list Back returns to from the editor, the methods of
+// the edited service are loaded then.
+func (m *Root) clearMethodsList() tea.Cmd {
+	_, cmd := m.methodsListView.Update(ShowMethodsList{})
+	return cmd
+}
+
+func (m *Root) showTargets() tea.Cmd {
+	m.openView(Targets)
+	return m.UpdateCurrentView(ShowTargetsList{
+		Configured: m.targets,
+		Recent:     m.commands.RecentTargets(),
+		Current:    m.commands.target,
+	})
+}
+
+// switchTarget connects to the target keeping the edited method open.
+func (m *Root) switchTarget(target config.Target) tea.Cmd {
+	cmds := []tea.Cmd{}
+	m.reloadMethod = ""
+	if m.previousView == Request || m.previousView == Response {
+		m.reloadMethod = m.requestView.method
+	}
+	_, cmd := m.responseView.Update(Back{})
+	cmds = append(cmds, cmd)
+	_, cmd = m.requestView.Update(Back{})
+	cmds = append(cmds, cmd)
+	_, cmd = m.servicesListView.Update(ShowServicesList{})
+	cmds = append(cmds, cmd)
+	_, cmd = m.methodsListView.Update(ShowMethodsList{})
+	cmds = append(cmds, cmd)
+
+	m.currentView = Services
+	m.connecting = true
+	m.statusView.SetTarget(target.Title())
+	cmds = append(cmds, m.commands.ClearStatusMsg())
+	cmds = append(cmds, m.commands.SwitchTarget(target))
+	return tea.Batch(cmds...)
+}
+
 func (m *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
 	switch msg := msg.(type) {
-	case NewStatus, NewStatusMessage, ClearStatusMsg:
-		m.statusView.Update(msg)
+	case NewStatus, NewStatusMessage, ClearStatusMsg, CallStarted, CallFinished, elapsedTick:
+		_, cmd := m.statusView.Update(msg)
+		cmds = append(cmds, cmd)
 	case ShowServicesList:
 		m.currentView = Services
 	case ShowMethodsList:
@@ -118,10 +361,87 @@ func (m *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentView = Response
 	case ResendRequest:
 		m.currentView = Request
+	case ReceivedHeaders, ReceivedResponse, ReceivedStatus, StreamOpened, StreamMessagesSent, StreamHalfClosed:
+		// the call keeps running under an overlay
+		if m.isOverlay() {
+			return m, m.updateView(m.previousView, msg)
+		}
+	case ChosenTarget:
+		return m, m.switchTarget(msg.Target)
+	case ChosenMethod:
+		return m, m.chooseMethod(msg.Method)
+	case RestoreRequest:
+		return m, m.restoreRequest(msg)
+	case ChosenRequest:
+		return m, m.chooseRequest(msg)
+	case ChosenEnvironment:
+		return m, m.chooseEnvironment(msg.Name)
+	case Connected:
+		// a connection to the previous target or environment is not needed anymore
+		if !m.commands.IsCurrent(msg.Target, msg.Environment) {
+			msg.Wrapper.Close()
+			return m, nil
+		}
+		m.connecting = false
+		m.commands.SetWrapper(msg.Wrapper)
+		m.commands.RememberTarget()
+		cmds = append(cmds, m.commands.SetConnectionState(msg.Wrapper, msg.Wrapper.State()))
+		if source, err := msg.Wrapper.Source(); err != nil {
+			cmds = append(cmds, m.commands.SetStatusMessage(err.Error(), StatusMsgError))
+		} else {
+			cmds = append(cmds, m.commands.SetStatusMessage("Connected, services from "+source, StatusMsgSuccess))
+		}
+		cmds = append(cmds, m.commands.SetStatusOK())
+		cmds = append(cmds, m.load())
+	case ConnectFailed:
+		if !m.commands.IsCurrent(msg.Target, msg.Environment) {
+			return m, nil
+		}
+		m.connecting = false
+		cmds = append(cmds, m.commands.SetConnectionState(nil, connectivity.TransientFailure))
+		cmds = append(cmds, m.commands.SetStatusMessage(msg.Error.Error()+" (press f5 to reconnect)", StatusMsgError))
+		cmds = append(cmds, m.commands.SetStatusOK())
+	case ConnectionState:
+		// states of a replaced connection are stale
+		if msg.wrapper != m.commands.grpc {
+			return m, nil
+		}
+		_, cmd := m.statusView.Update(msg)
+		cmds = append(cmds, cmd)
+		if msg.wrapper != nil && msg.State != connectivity.Shutdown {
+			cmds = append(cmds, m.commands.WatchConnection(msg.wrapper, msg.State))
+		}
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keyMap.ForceQuit) {
 			return m, tea.Quit
 		}
+		if key.Matches(msg, m.keyMap.Reconnect) {
+			if m.connecting {
+				return m, nil
+			}
+			m.connecting = true
+			m.commands.SetWrapper(nil)
+			return m, m.commands.Connect()
+		}
+		if key.Matches(msg, m.keyMap.Targets) {
+			return m, m.showTargets()
+		}
+		// other views use ctrl+p for editing
+		if key.Matches(msg, m.keyMap.Search) && (m.currentView == Services || m.currentView == Methods) {
+			return m, m.showSearch()
+		}
+		if key.Matches(msg, m.keyMap.Types) && (m.currentView == Request || m.currentView == Response) {
+			return m, m.showTypes()
+		}
+		if key.Matches(msg, m.keyMap.History) && (m.currentView == Request || m.currentView == Response) {
+			return m, m.showHistory()
+		}
+		if key.Matches(msg, m.keyMap.Saved) {
+			return m, m.showCollections()
+		}
+		if key.Matches(msg, m.keyMap.Envs) {
+			return m, m.showEnvironments()
+		}
 		if key.Matches(msg, m.keyMap.Back) {
 			cmds = append(cmds, m.UpdateCurrentView(Back{}))
 			switch m.currentView {
@@ -135,8 +455,15 @@ func (m *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, tea.Quit
 				}
 				m.currentView = Methods
+				// the search and saved requests open the editor before the methods were listed
+				if len(m.methodsListView.view.Items()) == 0 {
+					method := m.requestView.method
+					cmds = append(cmds, m.commands.LoadMethods(method[:strings.LastIndex(method, ".")]))
+				}
 			case Response:
 				m.currentView = Request
+			case Targets, Search, Types, History, Saved, Envs:
+				m.currentView = m.previousView
 			}
 			return m, tea.Batch(cmds...)
 		}
@@ -146,6 +473,12 @@ func (m *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.methodsListView.HandleWindowSize(msg)
 		m.requestView.HandleWindowSize(msg)
 		m.responseView.HandleWindowSize(msg)
+		m.targetsListView.HandleWindowSize(msg)
+		m.searchView.HandleWindowSize(msg)
+		m.typesView.HandleWindowSize(msg)
+		m.historyView.HandleWindowSize(msg)
+		m.collectionsView.HandleWindowSize(msg)
+		m.envsListView.HandleWindowSize(msg)
 		m.statusView.HandleWindowSize(msg)
 	case Err:
 		cmds = append(cmds, m.commands.SetStatusMessage(msg.Error.Error(), StatusMsgError))
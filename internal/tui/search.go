@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/profx5/jordi/internal/grpc"
+	"github.com/sahilm/fuzzy"
+)
+
+const searchInputHeight = 2
+
+var (
+	searchResultStyle   = lipgloss.NewStyle().PaddingLeft(4)
+	searchSelectedStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	searchMatchStyle    = lipgloss.NewStyle().Bold(true).Underline(true)
+)
+
+type (
+	SearchKeyMap struct {
+		Enter key.Binding
+		Up    key.Binding
+		Down  key.Binding
+	}
+	// SearchView fuzzy-matches the fully qualified names of all methods.
+	SearchView struct {
+		keyMap   SearchKeyMap
+		commands *Commands
+		input    textinput.Model
+		title    TitleView
+		help     HelpView
+		methods  []grpc.MethodInfo
+		names    []string
+		matches  fuzzy.Matches
+		selected int
+
+		width, height int
+	}
+)
+
+func DefaultSearchKeyMap() SearchKeyMap {
+	enter := key.NewBinding(key.WithKeys("enter"))
+	enter.SetHelp(`enter`, "open")
+
+	up := key.NewBinding(key.WithKeys("up", "ctrl+p"))
+	up.SetHelp(`↑`, "up")
+
+	down := key.NewBinding(key.WithKeys("down", "ctrl+n"))
+	down.SetHelp(`↓`, "down")
+
+	return SearchKeyMap{Enter: enter, Up: up, Down: down}
+}
+
+func (s SearchKeyMap) Bindings() []key.Binding {
+	return []key.Binding{s.Enter, s.Up, s.Down}
+}
+
+func NewSearchView(commands *Commands) *SearchView {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "package.Service.Method"
+
+	keyMap := DefaultSearchKeyMap()
+	return &SearchView{
+		keyMap:   keyMap,
+		commands: commands,
+		input:    input,
+		title:    NewTitleView("Search methods"),
+		help:     NewHelpView(keyMap),
+	}
+}
+
+func (s *SearchView) Init() tea.Cmd {
+	return nil
+}
+
+// filter matches the methods against the query, all of them match an empty query.
+func (s *SearchView) filter() {
+	s.selected = 0
+	query := strings.TrimSpace(s.input.Value())
+	if query == "" {
+		s.matches = make(fuzzy.Matches, 0, len(s.names))
+		for i, name := range s.names {
+			s.matches = append(s.matches, fuzzy.Match{Str: name, Index: i})
+		}
+		return
+	}
+	s.matches = fuzzy.Find(query, s.names)
+}
+
+func (s *SearchView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, s.keyMap.Enter):
+			if s.selected < len(s.matches) {
+				method := s.matches[s.selected].Str
+				return s, func() tea.Msg { return ChosenMethod{Method: method} }
+			}
+			return s, nil
+		case key.Matches(msg, s.keyMap.Up):
+			if s.selected > 0 {
+				s.selected--
+			}
+			return s, nil
+		case key.Matches(msg, s.keyMap.Down):
+			if s.selected < len(s.matches)-1 {
+				s.selected++
+			}
+			return s, nil
+		}
+		var cmd tea.Cmd
+		query := s.input.Value()
+		s.input, cmd = s.input.Update(msg)
+		if s.input.Value() != query {
+			s.filter()
+		}
+		return s, cmd
+	case ShowSearch:
+		s.methods, s.names = nil, nil
+		s.input.Reset()
+		s.input.Focus()
+		s.filter()
+		cmds = append(cmds, textinput.Blink)
+	case AllMethodsLoaded:
+		s.methods = msg.Methods
+		s.names = make([]string, 0, len(msg.Methods))
+		for _, method := range msg.Methods {
+			s.names = append(s.names, method.Name)
+		}
+		s.filter()
+		cmds = append(cmds, s.commands.SetStatusOK())
+		if msg.Err != nil {
+			cmds = append(cmds, s.commands.SetStatusMessage(msg.Err.Error(), StatusMsgError))
+		}
+	case Back:
+		s.input.Blur()
+	}
+	return s, tea.Batch(cmds...)
+}
+
+func (s *SearchView) renderMatch(match fuzzy.Match) string {
+	matched := map[int]bool{}
+	for _, i := range match.MatchedIndexes {
+		matched[i] = true
+	}
+	b := strings.Builder{}
+	for i, r := range match.Str {
+		if matched[i] {
+			b.WriteString(searchMatchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (s *SearchView) resultsView() string {
+	height := s.height - titleHeight - searchInputHeight - helpHeight
+	if height < 1 {
+		return ""
+	}
+	// keep the selected result visible
+	offset := 0
+	if s.selected >= height {
+		offset = s.selected - height + 1
+	}
+	lines := []string{}
+	for i := offset; i < len(s.matches) && i < offset+height; i++ {
+		if i == s.selected {
+			lines = append(lines, searchSelectedStyle.Render("│ "+s.renderMatch(s.matches[i])))
+		} else {
+			lines = append(lines, searchResultStyle.Render(s.renderMatch(s.matches[i])))
+		}
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *SearchView) View() string {
+	counter := responseHeaderStyle.Render(fmt.Sprintf("  %d/%d", len(s.matches), len(s.methods)))
+	input := lipgloss.NewStyle().PaddingLeft(2).Render(s.input.View())
+	return lipgloss.JoinVertical(lipgloss.Left, s.title.View(), input, counter, s.resultsView(), s.help.View())
+}
+
+func (s *SearchView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	s.width, s.height = msg.Width, msg.Height
+	s.input.Width = msg.Width - len(s.input.Prompt) - 4
+	s.help.SetWidth(msg.Width)
+}
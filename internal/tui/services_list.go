@@ -11,7 +11,8 @@ type (
 		Enter key.Binding
 	}
 	ServicesListItem struct {
-		Name string
+		Name    string
+		Comment string
 	}
 	ServicesListView struct {
 		keyMap   ServicesListKeyMap
@@ -29,7 +30,7 @@ func (i ServicesListItem) Title() string {
 }
 
 func (i ServicesListItem) Description() string {
-	return ""
+	return oneLine(i.Comment)
 }
 
 func NewServicesListView(commands *Commands) *ServicesListView {
@@ -55,15 +56,30 @@ func (m *ServicesListView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keyMap.Enter) {
-			return m, m.commands.LoadMethods(m.view.SelectedItem().(ServicesListItem).Name)
+			if item, ok := m.view.SelectedItem().(ServicesListItem); ok {
+				return m, m.commands.LoadMethods(item.Name)
+			}
+			return m, nil
 		}
 	case ShowServicesList:
 		items := []list.Item{}
+		documented := false
 		for _, service := range msg.Services {
-			items = append(items, ServicesListItem{Name: service})
+			items = append(items, ServicesListItem{Name: service.Name, Comment: service.Comment})
+			documented = documented || service.Comment != ""
 		}
+		// descriptions take a line per item, they are shown only when there are comments
+		delegate := list.NewDefaultDelegate()
+		delegate.ShowDescription = documented
+		m.view.SetDelegate(delegate)
 		cmds = append(cmds, m.view.SetItems(items))
 		cmds = append(cmds, m.commands.SetStatusOK())
+		if msg.Unavailable != nil {
+			cmds = append(cmds, m.commands.SetStatusMessage(msg.Unavailable.Error(), StatusMsgError))
+		}
+	case Err:
+		// the services failed to load
+		cmds = append(cmds, m.commands.SetStatusOK())
 	}
 	var cmd tea.Cmd
 	m.view, cmd = m.view.Update(msg)
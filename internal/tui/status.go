@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"fmt"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"google.golang.org/grpc/connectivity"
 )
 
 type StatusMsgType int
@@ -17,6 +21,7 @@ const (
 	StatusTypeError StatusType = iota
 
 	statusMsgAddWidth = 8
+	elapsedInterval   = 100 * time.Millisecond
 )
 
 var (
@@ -26,11 +31,21 @@ var (
 	statusStyle = lipgloss.NewStyle().
 			Padding(0, 2).
 			Bold(true)
+	statusTargetStyle = lipgloss.NewStyle().
+				Background(statusBackgorundColor).
+				Padding(0, 2)
 	statusTypeColorMap = map[StatusType]lipgloss.Color{
 		StatusTypeOK:    lipgloss.Color("#4e9a06"),
 		StatusTypeWarn:  lipgloss.Color("#e69b00"),
 		StatusTypeError: lipgloss.Color("#ff0000"),
 	}
+	connStateTypeMap = map[connectivity.State]StatusType{
+		connectivity.Idle:             StatusTypeWarn,
+		connectivity.Connecting:       StatusTypeWarn,
+		connectivity.Ready:            StatusTypeOK,
+		connectivity.TransientFailure: StatusTypeError,
+		connectivity.Shutdown:         StatusTypeError,
+	}
 	msgStylesMap = map[StatusMsgType]lipgloss.Style{
 		StatusMsgSuccess: lipgloss.NewStyle().
 			Background(statusBackgorundColor).
@@ -49,6 +64,14 @@ type (
 		statusType    StatusType
 		msg           string
 		statusMsgType StatusMsgType
+		// startedAt of the call in flight, zero when there is none
+		startedAt time.Time
+		tickID    int
+		connState string
+		connType  StatusType
+		target    string
+		// environment is the name of the selected environment
+		environment string
 
 		width int
 	}
@@ -78,28 +101,86 @@ func (s *StatusView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.statusMsgType = msg.Type
 	case ClearStatusMsg:
 		s.msg = ""
+	case CallStarted:
+		s.startedAt = msg.StartedAt
+		// a new tick chain replaces the one of the previous call
+		s.tickID++
+		return s, s.tick()
+	case ConnectionState:
+		s.connState = msg.State.String()
+		s.connType = connStateTypeMap[msg.State]
+	case CallFinished:
+		s.startedAt = time.Time{}
+	case elapsedTick:
+		if msg.id == s.tickID && !s.startedAt.IsZero() {
+			return s, s.tick()
+		}
 	}
 	return s, nil
 }
 
-func (s *StatusView) View() string {
-	statusColor, ok := statusTypeColorMap[s.statusType]
+func (s *StatusView) tick() tea.Cmd {
+	id := s.tickID
+	return tea.Tick(elapsedInterval, func(time.Time) tea.Msg {
+		return elapsedTick{id: id}
+	})
+}
+
+func (s *StatusView) status() string {
+	if s.startedAt.IsZero() {
+		return s.currentStatus
+	}
+	elapsed := time.Since(s.startedAt).Truncate(elapsedInterval)
+	return fmt.Sprintf("%s %s", s.currentStatus, elapsed)
+}
+
+func statusColor(statusType StatusType) lipgloss.Color {
+	color, ok := statusTypeColorMap[statusType]
 	if !ok {
-		statusColor = lipgloss.Color("#ffffff")
+		color = lipgloss.Color("#ffffff")
 	}
-	views := []string{statusStyle.Background(statusColor).Render(s.currentStatus)}
+	return color
+}
+
+func (s *StatusView) SetTarget(target string) {
+	s.target = target
+}
+
+func (s *StatusView) SetEnvironment(name string) {
+	s.environment = name
+}
+
+// targetView is the target with the environment its requests are rendered with.
+func (s *StatusView) targetView() string {
+	if s.environment == "" {
+		return s.target
+	}
+	return fmt.Sprintf("%s · %s", s.environment, s.target)
+}
+
+func (s *StatusView) View() string {
+	status := s.status()
+	views := []string{statusStyle.Background(statusColor(s.statusType)).Render(status)}
 	if s.msg != "" {
 		msg := s.msg
-		maxWidth := s.width - len(s.currentStatus) - statusMsgAddWidth
+		maxWidth := s.width - len(status) - len(s.targetView()) - len(s.connState) - statusMsgAddWidth*2
 		if maxWidth > 0 && len(msg) > maxWidth {
 			msg = msg[:maxWidth]
 		}
 		views = append(views, msgStylesMap[s.statusMsgType].Render(msg))
 	}
+	left := lipgloss.JoinHorizontal(lipgloss.Top, views...)
+	if s.connState == "" {
+		return statusBarStyle.Width(s.width).Render(left)
+	}
 
-	return statusBarStyle.Width(s.width).Render(
-		lipgloss.JoinHorizontal(lipgloss.Top, views...),
-	)
+	// the connection state is aligned to the right edge
+	conn := statusStyle.Background(statusColor(s.connType)).Render(s.connState)
+	if target := s.targetView(); target != "" {
+		conn = lipgloss.JoinHorizontal(lipgloss.Top, statusTargetStyle.Render(target), conn)
+	}
+	right := statusBarStyle.Width(s.width - lipgloss.Width(left)).Align(lipgloss.Right).Render(conn)
+	return statusBarStyle.Width(s.width).Render(lipgloss.JoinHorizontal(lipgloss.Top, left, right))
 }
 
 func (s *StatusView) HandleWindowSize(msg tea.WindowSizeMsg) {
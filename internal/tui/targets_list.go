@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/profx5/jordi/internal/config"
+)
+
+type (
+	TargetsListKeyMap struct {
+		Enter key.Binding
+	}
+	TargetsListItem struct {
+		Target  config.Target
+		Source  string
+		Current bool
+	}
+	TargetsListView struct {
+		keyMap   TargetsListKeyMap
+		commands *Commands
+		view     list.Model
+	}
+)
+
+func (i TargetsListItem) FilterValue() string {
+	return i.Target.Title() + " " + i.Target.Address
+}
+
+func (i TargetsListItem) Title() string {
+	if i.Current {
+		return i.Target.Title() + " (current)"
+	}
+	return i.Target.Title()
+}
+
+func (i TargetsListItem) Description() string {
+	parts := []string{i.Target.Address}
+	if i.Target.Plaintext {
+		parts = append(parts, "plaintext")
+	}
+	parts = append(parts, i.Source)
+	return strings.Join(parts, " · ")
+}
+
+func NewTargetsListView(commands *Commands) *TargetsListView {
+	view := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	view.Title = "Targets"
+
+	return &TargetsListView{
+		keyMap:   TargetsListKeyMap{Enter: key.NewBinding(key.WithKeys("enter"))},
+		commands: commands,
+		view:     view,
+	}
+}
+
+func (m *TargetsListView) Init() tea.Cmd {
+	return nil
+}
+
+// targetItems lists the configured targets first, then the recent ones not in the config.
+func targetItems(msg ShowTargetsList) []list.Item {
+	items := []list.Item{}
+	seen := []config.Target{}
+	add := func(target config.Target, source string) {
+		for _, other := range seen {
+			if other.Same(target) {
+				return
+			}
+		}
+		seen = append(seen, target)
+		items = append(items, TargetsListItem{Target: target, Source: source, Current: target.Same(msg.Current)})
+	}
+	for _, target := range msg.Configured {
+		add(target, "configured")
+	}
+	for _, target := range msg.Recent {
+		add(target, "recent")
+	}
+	return items
+}
+
+func (m *TargetsListView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keyMap.Enter) && m.view.FilterState() != list.Filtering {
+			if item, ok := m.view.SelectedItem().(TargetsListItem); ok {
+				return m, func() tea.Msg { return ChosenTarget{Target: item.Target} }
+			}
+			return m, nil
+		}
+	case ShowTargetsList:
+		m.view.ResetFilter()
+		m.view.Select(0)
+		cmds = append(cmds, m.view.SetItems(targetItems(msg)))
+	}
+	var cmd tea.Cmd
+	m.view, cmd = m.view.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *TargetsListView) View() string {
+	return m.view.View()
+}
+
+func (m *TargetsListView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	m.view.SetWidth(msg.Width)
+	m.view.SetHeight(msg.Height)
+}
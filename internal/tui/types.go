@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/profx5/jordi/internal/grpc"
+)
+
+const typeRefsHeaderHeight = 2
+
+var (
+	typeRefsStyle        = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true, false, false, false)
+	typeRefStyle         = lipgloss.NewStyle().PaddingLeft(4)
+	typeRefSelectedStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+)
+
+type (
+	TypesKeyMap struct {
+		Open     key.Binding
+		Parent   key.Binding
+		Up       key.Binding
+		Down     key.Binding
+		PageUp   key.Binding
+		PageDown key.Binding
+	}
+	// TypesView browses the method types, following field types to their definitions.
+	TypesView struct {
+		keyMap   TypesKeyMap
+		commands *Commands
+		view     viewport.Model
+		title    TitleView
+		help     HelpView
+		// stack of the opened symbols, the last one is shown
+		stack    []string
+		info     grpc.SymbolInfo
+		selected int
+
+		width, height int
+	}
+)
+
+func DefaultTypesKeyMap() TypesKeyMap {
+	open := key.NewBinding(key.WithKeys("enter"))
+	open.SetHelp(`enter`, "open type")
+
+	parent := key.NewBinding(key.WithKeys("backspace"))
+	parent.SetHelp(`backspace`, "back")
+
+	up := key.NewBinding(key.WithKeys("up"))
+	up.SetHelp(`↑`, "up")
+
+	down := key.NewBinding(key.WithKeys("down"))
+	down.SetHelp(`↓`, "down")
+
+	pageUp := key.NewBinding(key.WithKeys("pgup"))
+	pageUp.SetHelp(`pgup`, "scroll up")
+
+	pageDown := key.NewBinding(key.WithKeys("pgdown"))
+	pageDown.SetHelp(`pgdown`, "scroll down")
+
+	return TypesKeyMap{
+		Open:     open,
+		Parent:   parent,
+		Up:       up,
+		Down:     down,
+		PageUp:   pageUp,
+		PageDown: pageDown,
+	}
+}
+
+func (t TypesKeyMap) Bindings() []key.Binding {
+	return []key.Binding{t.Open, t.Parent, t.Up, t.Down, t.PageUp, t.PageDown}
+}
+
+func NewTypesView(commands *Commands) *TypesView {
+	keyMap := DefaultTypesKeyMap()
+	return &TypesView{
+		keyMap:   keyMap,
+		commands: commands,
+		view:     viewport.New(0, 0),
+		title:    NewTitleView("Types"),
+		help:     NewHelpView(keyMap),
+	}
+}
+
+func (t *TypesView) Init() tea.Cmd {
+	return nil
+}
+
+func (t *TypesView) breadcrumbs() string {
+	names := make([]string, 0, len(t.stack))
+	for _, name := range t.stack {
+		names = append(names, getShortMethodName(name))
+	}
+	return strings.Join(names, " › ")
+}
+
+func (t *TypesView) open(symbol string) tea.Cmd {
+	t.stack = append(t.stack, symbol)
+	t.title.SetTitle(t.breadcrumbs())
+	return t.commands.DescribeSymbol(symbol)
+}
+
+func (t *TypesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, t.keyMap.Open):
+			if t.selected < len(t.info.References) {
+				return t, t.open(t.info.References[t.selected].Type)
+			}
+		case key.Matches(msg, t.keyMap.Parent):
+			if len(t.stack) > 1 {
+				parent := t.stack[len(t.stack)-2]
+				t.stack = t.stack[:len(t.stack)-2]
+				return t, t.open(parent)
+			}
+		case key.Matches(msg, t.keyMap.Up):
+			if t.selected > 0 {
+				t.selected--
+			}
+		case key.Matches(msg, t.keyMap.Down):
+			if t.selected < len(t.info.References)-1 {
+				t.selected++
+			}
+		case key.Matches(msg, t.keyMap.PageUp):
+			t.view.ViewUp()
+		case key.Matches(msg, t.keyMap.PageDown):
+			t.view.ViewDown()
+		}
+	case ShowTypes:
+		t.stack = nil
+		t.info = grpc.SymbolInfo{}
+		t.view.SetContent("")
+		return t, t.open(msg.Symbol)
+	case SymbolDescribed:
+		// a slow answer for a symbol the user already left
+		if len(t.stack) == 0 || msg.Info.Name != t.stack[len(t.stack)-1] {
+			return t, nil
+		}
+		t.info = msg.Info
+		t.selected = 0
+		t.view.SetContent(msg.Info.Text)
+		t.view.GotoTop()
+		return t, t.commands.SetStatusOK()
+	case Err:
+		// the symbol failed to resolve
+		return t, t.commands.SetStatusOK()
+	}
+	return t, nil
+}
+
+func (t *TypesView) refsHeight() int {
+	if len(t.info.References) == 0 {
+		return 0
+	}
+	height := len(t.info.References)
+	if maxHeight := t.height / 3; height > maxHeight {
+		height = maxHeight
+	}
+	return height + typeRefsHeaderHeight
+}
+
+func (t *TypesView) refsView() string {
+	height := t.refsHeight() - typeRefsHeaderHeight
+	// keep the selected reference visible
+	offset := 0
+	if t.selected >= height {
+		offset = t.selected - height + 1
+	}
+	lines := []string{responseHeaderStyle.Render(fmt.Sprintf("  Field types (%d)", len(t.info.References)))}
+	for i := offset; i < len(t.info.References) && i < offset+height; i++ {
+		ref := t.info.References[i]
+		line := fmt.Sprintf("%s → %s", ref.Field, ref.Type)
+		if i == t.selected {
+			lines = append(lines, typeRefSelectedStyle.Render("│ "+line))
+		} else {
+			lines = append(lines, typeRefStyle.Render(line))
+		}
+	}
+	return typeRefsStyle.Width(t.width).Render(strings.Join(lines, "\n"))
+}
+
+func (t *TypesView) View() string {
+	t.view.Width = t.width
+	t.view.Height = t.height - titleHeight - helpHeight - t.refsHeight()
+	views := []string{t.title.View(), t.view.View()}
+	if len(t.info.References) > 0 {
+		views = append(views, t.refsView())
+	}
+	views = append(views, t.help.View())
+	return lipgloss.JoinVertical(lipgloss.Left, views...)
+}
+
+func (t *TypesView) HandleWindowSize(msg tea.WindowSizeMsg) {
+	t.width, t.height = msg.Width, msg.Height
+	t.help.SetWidth(msg.Width)
+}
@@ -1,14 +1,27 @@
 package tui
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/profx5/jordi/internal/collection"
+	"google.golang.org/grpc/metadata"
 )
 
 func getShortMethodName(methodName string) string {
 	return methodName[strings.LastIndex(methodName, ".")+1:]
 }
 
+// oneLine joins the lines of a comment for list descriptions.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 func countLines(s string) int {
 	return strings.Count(s, "\n")
 }
@@ -16,3 +29,137 @@ func countLines(s string) int {
 func checkJSON(s string) error {
 	return json.Unmarshal([]byte(s), &struct{}{})
 }
+
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding,
+}
+
+func headerName(header string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(header, ":", 2)[0]))
+}
+
+// parseHeaders parses the metadata editor content, one 'name: value' header per line.
+// Values may have placeholders, binary ones are checked by checkBinaryHeaders once rendered.
+func parseHeaders(text string) ([]string, error) {
+	headers := []string{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		name := headerName(line)
+		if len(parts) != 2 || name == "" {
+			return nil, fmt.Errorf("metadata line %d: expected 'name: value'", i+1)
+		}
+		headers = append(headers, fmt.Sprintf("%s: %s", name, strings.TrimSpace(parts[1])))
+	}
+	return headers, nil
+}
+
+// checkBinaryHeaders verifies that values of binary headers (names ending with -bin) are base64-encoded.
+func checkBinaryHeaders(headers []string) error {
+	for _, header := range headers {
+		name := headerName(header)
+		value := strings.TrimSpace(strings.SplitN(header, ":", 2)[1])
+		if strings.HasSuffix(name, "-bin") && !isBase64(value) {
+			return fmt.Errorf("value of binary header %q is not base64", name)
+		}
+	}
+	return nil
+}
+
+func isBase64(s string) bool {
+	for _, enc := range base64Encodings {
+		if _, err := enc.DecodeString(s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeHeaders replaces headers of base with the same named ones of override.
+func mergeHeaders(base, override []string) []string {
+	overridden := map[string]bool{}
+	for _, header := range override {
+		overridden[headerName(header)] = true
+	}
+	merged := []string{}
+	for _, header := range base {
+		if !overridden[headerName(header)] {
+			merged = append(merged, header)
+		}
+	}
+	return append(merged, override...)
+}
+
+// formatMetadata renders metadata as sorted 'name: value' lines, binary values are base64-encoded.
+func formatMetadata(md metadata.MD) string {
+	names := make([]string, 0, len(md))
+	for name := range md {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{}
+	for _, name := range names {
+		for _, value := range md[name] {
+			if strings.HasSuffix(name, "-bin") {
+				value = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatStatus joins the status code with its message.
+func formatStatus(code, message string) string {
+	if message == "" {
+		return code
+	}
+	return fmt.Sprintf("%s: %s", code, message)
+}
+
+// parseDeadline parses the request deadline, either a duration like "1m30s"
+// or a number of seconds. Empty value means no deadline.
+func parseDeadline(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	deadline, err := time.ParseDuration(s)
+	if err != nil {
+		seconds, parseErr := strconv.ParseFloat(s, 64)
+		if parseErr != nil {
+			return 0, fmt.Errorf("invalid deadline %q: expected a duration like 5s or 1m30s", s)
+		}
+		deadline = time.Duration(seconds * float64(time.Second))
+	}
+	if deadline < 0 {
+		return 0, fmt.Errorf("invalid deadline %q: must not be negative", s)
+	}
+	return deadline, nil
+}
+
+// savedName joins the collection and the request name as the save prompt shows them.
+func savedName(collectionName, name string) string {
+	return collectionName + "/" + name
+}
+
+// parseSavedName splits the save prompt value, requests without a collection go to the default one.
+func parseSavedName(s string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) == 1 {
+		return collection.DefaultName, strings.TrimSpace(parts[0])
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+// formatElapsed rounds the call duration for display.
+func formatElapsed(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
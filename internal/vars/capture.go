@@ -0,0 +1,155 @@
+package vars
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nameRe is an identifier, variables are referenced as {{name}}
+var nameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Capture copies the value at Path of a response into the variable Name.
+type Capture struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func (c Capture) String() string {
+	return c.Name + " = " + c.Path
+}
+
+// IsValidName tells whether the name can be used as a {{name}} placeholder.
+func IsValidName(name string) bool {
+	return nameRe.MatchString(name)
+}
+
+// ParseCapture parses a capture rule written as 'name = $.path.to[0].field'.
+func ParseCapture(text string) (Capture, error) {
+	parts := strings.SplitN(text, "=", 2)
+	if len(parts) != 2 {
+		return Capture{}, errors.Errorf("invalid capture '%s': expected 'name = $.path'", text)
+	}
+	capture := Capture{Name: strings.TrimSpace(parts[0]), Path: strings.TrimSpace(parts[1])}
+	if !IsValidName(capture.Name) {
+		return Capture{}, errors.Errorf("invalid capture variable name '%s'", capture.Name)
+	}
+	if capture.Path == "" {
+		return Capture{}, errors.Errorf("invalid capture '%s': empty path", text)
+	}
+	if _, err := parsePath(capture.Path); err != nil {
+		return Capture{}, err
+	}
+	return capture, nil
+}
+
+// Extract returns the value at the JSONPath-like path of the JSON document, e.g.
+// $.items[0].id or $["key.with.dots"]. Strings are returned unquoted, objects and
+// arrays as compact JSON.
+func Extract(document, path string) (string, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", errors.Wrap(err, "failed to decode the response")
+	}
+	for _, step := range steps {
+		switch current := value.(type) {
+		case map[string]any:
+			field, ok := current[step.field]
+			if step.isIndex || !ok {
+				return "", errors.Errorf("%s: %s not found", path, step)
+			}
+			value = field
+		case []any:
+			index := step.index
+			if index < 0 {
+				index += len(current)
+			}
+			if !step.isIndex || index < 0 || index >= len(current) {
+				return "", errors.Errorf("%s: %s not found", path, step)
+			}
+			value = current[index]
+		default:
+			return "", errors.Errorf("%s: %s not found", path, step)
+		}
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+type pathStep struct {
+	field   string
+	index   int
+	isIndex bool
+}
+
+func (s pathStep) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return strconv.Quote(s.field)
+}
+
+// parsePath splits the path into fields and indexes, the leading $ is optional.
+func parsePath(path string) ([]pathStep, error) {
+	invalid := func() ([]pathStep, error) {
+		return nil, errors.Errorf("invalid path '%s'", path)
+	}
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		// the leading dot of the first field may be omitted
+		rest = "." + rest
+	}
+	steps := []pathStep{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" {
+				return invalid()
+			}
+			steps = append(steps, pathStep{field: field})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return invalid()
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, `'`) {
+				if len(inner) < 2 || inner[len(inner)-1] != inner[0] {
+					return invalid()
+				}
+				steps = append(steps, pathStep{field: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return invalid()
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return invalid()
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,60 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `{
+  "user": {"id": "u-42", "age": 42, "active": true, "tags": ["a", "b"]},
+  "items": [{"id": 1}, {"id": 12345678901234567890}],
+  "key.with.dots": "dotted",
+  "empty": null
+}`
+
+func TestExtract(t *testing.T) {
+	for _, tt := range []struct {
+		path     string
+		expected string
+	}{
+		{"$.user.id", "u-42"},
+		{"user.id", "u-42"},
+		{"$.user.age", "42"},
+		{"$.user.active", "true"},
+		{"$.user.tags", `["a","b"]`},
+		{"$.user.tags[1]", "b"},
+		{"$.items[0].id", "1"},
+		{"$.items[-1].id", "12345678901234567890"},
+		{`$["key.with.dots"]`, "dotted"},
+		{`$['user']["id"]`, "u-42"},
+		{"$.empty", "null"},
+		{"$", `{"empty":null,"items":[{"id":1},{"id":12345678901234567890}],"key.with.dots":"dotted","user":{"active":true,"age":42,"id":"u-42","tags":["a","b"]}}`},
+	} {
+		value, err := Extract(document, tt.path)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.expected, value, tt.path)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	for _, path := range []string{"$.missing", "$.user.id.more", "$.items[2]", "$.user[0]", "$.items.id", "$.", "$[", "$[x]", `$["open]`} {
+		_, err := Extract(document, path)
+		assert.Error(t, err, path)
+	}
+	_, err := Extract("not json", "$.id")
+	assert.Error(t, err)
+}
+
+func TestParseCapture(t *testing.T) {
+	capture, err := ParseCapture(" user_id = $.user.id ")
+	require.NoError(t, err)
+	assert.Equal(t, Capture{Name: "user_id", Path: "$.user.id"}, capture)
+	assert.Equal(t, "user_id = $.user.id", capture.String())
+
+	for _, text := range []string{"user_id", "user-id = $.id", " = $.id", "id = ", "id = $.", "id = $[x]"} {
+		_, err := ParseCapture(text)
+		assert.Error(t, err, text)
+	}
+}
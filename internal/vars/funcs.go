@@ -0,0 +1,95 @@
+package vars
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const randAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// funcs are evaluated on every render, so each sent request gets fresh values.
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"uuid":       newUUID,
+		"now":        now,
+		"timestamp":  timestamp,
+		"unix":       func() int64 { return time.Now().Unix() },
+		"randInt":    randInt,
+		"randString": randString,
+		"base64file": base64File,
+		"env":        os.Getenv,
+	}
+}
+
+// newUUID generates a random (version 4) UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// now formats the current UTC time, RFC3339 unless a Go layout is given.
+func now(layout ...string) (string, error) {
+	switch len(layout) {
+	case 0:
+		return time.Now().UTC().Format(time.RFC3339), nil
+	case 1:
+		return time.Now().UTC().Format(layout[0]), nil
+	}
+	return "", errors.New("now expects at most one layout")
+}
+
+// timestamp is the current time as the quoted JSON value of a google.protobuf.Timestamp.
+func timestamp() (string, error) {
+	b, err := protojson.Marshal(timestamppb.Now())
+	return string(b), err
+}
+
+// randInt returns a random integer in [min, max).
+func randInt(min, max int) (int, error) {
+	if max <= min {
+		return 0, errors.Errorf("randInt: max %d must be greater than min %d", max, min)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)))
+	if err != nil {
+		return 0, err
+	}
+	return min + int(n.Int64()), nil
+}
+
+// randString returns a random alphanumeric string of the given length.
+func randString(length int) (string, error) {
+	if length < 0 {
+		return "", errors.Errorf("randString: negative length %d", length)
+	}
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(randAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = randAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func base64File(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "base64file: failed to read '%s'", path)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
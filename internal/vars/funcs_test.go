@@ -0,0 +1,80 @@
+package vars
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, text string) string {
+	rendered, err := Render(text, nil)
+	require.NoError(t, err, text)
+	return rendered
+}
+
+func TestUUID(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := render(t, "{{uuid}}"), render(t, "{{uuid}}")
+	assert.Regexp(t, uuidPattern, first)
+	assert.Regexp(t, uuidPattern, second)
+	assert.NotEqual(t, first, second)
+}
+
+func TestTime(t *testing.T) {
+	before := time.Now().Add(-time.Second)
+
+	parsed, err := time.Parse(time.RFC3339, render(t, "{{now}}"))
+	require.NoError(t, err)
+	assert.True(t, parsed.After(before))
+
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, render(t, `{{now "2006-01-02"}}`))
+
+	timestamp := render(t, "{{timestamp}}")
+	require.True(t, strings.HasPrefix(timestamp, `"`) && strings.HasSuffix(timestamp, `Z"`), timestamp)
+	parsed, err = time.Parse(time.RFC3339Nano, strings.Trim(timestamp, `"`))
+	require.NoError(t, err)
+	assert.True(t, parsed.After(before))
+
+	unix, err := strconv.ParseInt(render(t, "{{unix}}"), 10, 64)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, unix, before.Unix())
+}
+
+func TestRandom(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		n, err := strconv.Atoi(render(t, "{{randInt 5 8}}"))
+		require.NoError(t, err)
+		assert.True(t, n >= 5 && n < 8, n)
+	}
+	_, err := Render("{{randInt 5 5}}", nil)
+	assert.Error(t, err)
+
+	assert.Regexp(t, `^[a-zA-Z0-9]{12}$`, render(t, "{{randString 12}}"))
+	assert.Equal(t, "", render(t, "{{randString 0}}"))
+}
+
+func TestBase64FileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.bin")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hello")), render(t, `{{base64file "`+path+`"}}`))
+
+	_, err := Render(`{{base64file "missing.bin"}}`, nil)
+	assert.Error(t, err)
+
+	t.Setenv("JORDI_TEST_TOKEN", "secret")
+	assert.Equal(t, "Bearer secret", render(t, `Bearer {{env "JORDI_TEST_TOKEN"}}`))
+}
+
+func TestVariablesHideFuncs(t *testing.T) {
+	rendered, err := Render("{{uuid}}", map[string]string{"uuid": "fixed"})
+	require.NoError(t, err)
+	assert.Equal(t, "fixed", rendered)
+}
@@ -0,0 +1,68 @@
+package vars
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	leftDelim = "{{"
+	// escapedDelim is a literal "{{" of the text, e.g. a template of the request payload
+	escapedDelim = `\{{`
+)
+
+// Render resolves the {{name}} placeholders of the text with the variables.
+// The text is a text/template, each variable is a function without arguments,
+// variables hide the functions of the same name, e.g. {{uuid}}.
+// Literal braces are escaped with a backslash: \{{name}} renders as {{name}}.
+func Render(text string, variables map[string]string) (string, error) {
+	return execute(text, funcs(), variables)
+}
+
+// Preview resolves the variables of the text like Render, the function calls are
+// kept as written since their values change on every render.
+func Preview(text string, variables map[string]string) (string, error) {
+	calls := template.FuncMap{}
+	for name := range funcs() {
+		name := name
+		calls[name] = func(args ...interface{}) string { return formatCall(name, args) }
+	}
+	return execute(text, calls, variables)
+}
+
+func formatCall(name string, args []interface{}) string {
+	b := strings.Builder{}
+	b.WriteString(leftDelim + name)
+	for _, arg := range args {
+		if s, ok := arg.(string); ok {
+			fmt.Fprintf(&b, " %q", s)
+		} else {
+			fmt.Fprintf(&b, " %v", arg)
+		}
+	}
+	b.WriteString("}}")
+	return b.String()
+}
+
+func execute(text string, funcs template.FuncMap, variables map[string]string) (string, error) {
+	if !strings.Contains(text, leftDelim) {
+		return text, nil
+	}
+	text = strings.ReplaceAll(text, escapedDelim, `{{"{{"}}`)
+	for name, value := range variables {
+		value := value
+		funcs[name] = func() string { return value }
+	}
+	tmpl, err := template.New("request").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", errors.Wrap(err, `failed to parse placeholders, escape literal braces as \{{`)
+	}
+	b := strings.Builder{}
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return "", errors.Wrap(err, `failed to render placeholders, escape literal braces as \{{`)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,72 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	variables := map[string]string{"tenant": "acme", "id": "42", "host": "staging.example.com"}
+
+	for _, tt := range []struct {
+		text     string
+		expected string
+	}{
+		{`{"id": "plain"}`, `{"id": "plain"}`},
+		{`{"tenant": "{{tenant}}", "id": {{id}}}`, `{"tenant": "acme", "id": 42}`},
+		{`{{ tenant }}`, `acme`},
+		{`{{host}}:443`, `staging.example.com:443`},
+		{`x-tenant: {{tenant}}`, `x-tenant: acme`},
+	} {
+		rendered, err := Render(tt.text, variables)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.expected, rendered, tt.text)
+	}
+}
+
+func TestRenderEscaped(t *testing.T) {
+	for _, tt := range []struct {
+		text     string
+		expected string
+	}{
+		{`{"tmpl": "Hi \{{name}}"}`, `{"tmpl": "Hi {{name}}"}`},
+		{`{"tmpl": "\{{name}} of {{tenant}}"}`, `{"tmpl": "{{name}} of acme"}`},
+	} {
+		rendered, err := Render(tt.text, map[string]string{"tenant": "acme"})
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.expected, rendered, tt.text)
+	}
+
+	_, err := Render(`{"tmpl": "Hi {{name}}"}`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `escape literal braces as \{{`)
+}
+
+func TestPreview(t *testing.T) {
+	variables := map[string]string{"tenant": "acme", "uuid": "fixed"}
+
+	for _, tt := range []struct {
+		text     string
+		expected string
+	}{
+		{`{"tenant": "{{tenant}}", "id": "{{randString 8}}"}`, `{"tenant": "acme", "id": "{{randString 8}}"}`},
+		{`{"avatar": "{{base64file "a.png"}}", "at": {{timestamp}}}`, `{"avatar": "{{base64file "a.png"}}", "at": {{timestamp}}}`},
+		{`{{randInt 1 9}} {{now "2006-01-02"}}`, `{{randInt 1 9}} {{now "2006-01-02"}}`},
+		{`{{uuid}}`, `fixed`},
+	} {
+		rendered, err := Preview(tt.text, variables)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.expected, rendered, tt.text)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	_, err := Render(`{"id": "{{missing}}"}`, map[string]string{"id": "42"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `function "missing" not defined`)
+
+	_, err = Render(`{"id": "{{id"}`, nil)
+	assert.Error(t, err)
+}